@@ -0,0 +1,246 @@
+// Package wordladders holds regression tests that run the word ladder
+// programs against Martin Gardner's doublets.
+package wordladders
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "regenerate golden files from the reference program")
+
+// doublets is the list of Martin Gardner's doublets held in
+// content/post/word_ladder.md.
+var doublets = [][2]string{
+	{"rogue", "beast"},
+	{"shoes", "crust"},
+	{"grass", "green"},
+	{"black", "white"},
+	{"costs", "pence"},
+	{"quell", "bravo"},
+	{"kettle", "holder"},
+	{"furies", "barrel"},
+	{"tears", "smile"},
+	{"pitch", "tents"},
+	{"flour", "bread"},
+	{"raven", "miser"},
+	{"wheat", "bread"},
+	{"steal", "coins"},
+	{"beans", "shelf"},
+}
+
+// programs are the word ladder programs run on each doublet. Programs
+// that print all the shortest ladders print one ladder per line, and
+// otherwise one word of a single ladder per line.
+var programs = []struct {
+	name string
+	args []string
+	all  bool
+}{
+	{name: "words-0"},
+	{name: "words-0", args: []string{"-astar"}},
+	{name: "words-1"},
+	{name: "words-1", args: []string{"-astar"}},
+	{name: "words-1a", all: true},
+	{name: "words-2"},
+	{name: "words-2", args: []string{"-astar"}},
+	{name: "words-2a", all: true},
+	{name: "words-2b"},
+	{name: "words-2b", args: []string{"-all"}, all: true},
+	{name: "words-2f"},
+}
+
+// reference is the program whose output is written to the
+// golden files by the -update flag.
+const reference = "words-2a"
+
+const dictionary = "testdata/doublets.txt"
+
+// bin is the directory holding the built programs.
+var bin string
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	os.Exit(run(m))
+}
+
+func run(m *testing.M) int {
+	var err error
+	bin, err = os.MkdirTemp("", "word_ladders")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to make program directory: %v\n", err)
+		return 1
+	}
+	defer os.RemoveAll(bin)
+	built := make(map[string]bool)
+	for _, p := range programs {
+		if built[p.name] {
+			continue
+		}
+		built[p.name] = true
+		out, err := exec.Command("go", "build", "-o", filepath.Join(bin, p.name), "./"+p.name).CombinedOutput()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to build %s: %v\n%s", p.name, err, out)
+			return 1
+		}
+	}
+	return m.Run()
+}
+
+func TestDoublets(t *testing.T) {
+	for _, d := range doublets {
+		golden := filepath.Join("testdata", "golden", d[0]+"-"+d[1]+".golden")
+		if *update {
+			ladders, err := programLadders(reference, nil, true, d)
+			if err != nil {
+				t.Fatalf("failed to run %s for %s: %v", reference, d, err)
+			}
+			err = os.WriteFile(golden, formatGolden(ladders), 0o664)
+			if err != nil {
+				t.Fatalf("failed to write golden file: %v", err)
+			}
+		}
+		want, err := readGolden(golden)
+		if err != nil {
+			t.Fatalf("failed to read golden file: %v", err)
+		}
+
+		for _, p := range programs {
+			got, err := programLadders(p.name, p.args, p.all, d)
+			if err != nil {
+				t.Errorf("failed to run %s %s for %s: %v", p.name, p.args, d, err)
+				continue
+			}
+			if p.all {
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("unexpected ladders from %s %s for %s:\ngot: %q\nwant:%q", p.name, p.args, d, got, want)
+				}
+				continue
+			}
+			switch {
+			case len(want) == 0:
+				if len(got) != 0 {
+					t.Errorf("unexpected ladder from %s %s for %s: got:%q want none", p.name, p.args, d, got)
+				}
+			case len(got) != 1:
+				t.Errorf("unexpected number of ladders from %s %s for %s: got:%d want:1", p.name, p.args, d, len(got))
+			case steps(got[0]) != steps(want[0]):
+				t.Errorf("unexpected ladder length from %s %s for %s: got:%d want:%d", p.name, p.args, d, steps(got[0]), steps(want[0]))
+			case !contains(want, got[0]):
+				t.Errorf("unexpected ladder from %s %s for %s: got:%q want one of:%q", p.name, p.args, d, got[0], want)
+			}
+		}
+	}
+}
+
+// programLadders runs the named program with the given arguments on the doublet
+// d using the dictionary fixture, and returns the sorted ladders it prints
+// with the words of each ladder separated by spaces. Programs that exit
+// with status 1 after reporting that there is no ladder return no ladders.
+func programLadders(name string, args []string, all bool, d [2]string) ([]string, error) {
+	f, err := os.Open(dictionary)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	cmd := exec.Command(filepath.Join(bin, name), append(args, "-first", d[0], "-last", d[1])...)
+	cmd.Stdin = f
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	var exit *exec.ExitError
+	if errors.As(err, &exit) && exit.ExitCode() == 1 && stdout.Len() == 0 &&
+		bytes.Contains(stderr.Bytes(), []byte(fmt.Sprintf("no ladder from %q to %q", d[0], d[1]))) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%v: %s", err, stderr.Bytes())
+	}
+
+	if !all {
+		words := strings.Fields(stdout.String())
+		if len(words) == 0 {
+			return nil, nil
+		}
+		return []string{strings.Join(words, " ")}, nil
+	}
+	var ladders []string
+	for _, l := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		if l == "" {
+			continue
+		}
+		ladders = append(ladders, strings.Trim(l, "[]"))
+	}
+	sort.Strings(ladders)
+	return ladders, nil
+}
+
+// formatGolden returns the golden file contents for the given ladders. The
+// first line holds the number of steps in the shortest ladders, or none if
+// there is no ladder, and is followed by the ladders, one per line.
+func formatGolden(ladders []string) []byte {
+	var buf bytes.Buffer
+	if len(ladders) == 0 {
+		fmt.Fprintln(&buf, "steps: none")
+	} else {
+		fmt.Fprintf(&buf, "steps: %d\n", steps(ladders[0]))
+	}
+	for _, l := range ladders {
+		fmt.Fprintln(&buf, l)
+	}
+	return buf.Bytes()
+}
+
+// readGolden returns the ladders held in the named golden file after
+// checking that they have the number of steps recorded in the file.
+func readGolden(name string) ([]string, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if !strings.HasPrefix(lines[0], "steps: ") {
+		return nil, fmt.Errorf("%s: missing steps line", name)
+	}
+	n := strings.TrimPrefix(lines[0], "steps: ")
+	ladders := lines[1:]
+	if n == "none" {
+		if len(ladders) != 0 {
+			return nil, fmt.Errorf("%s: unexpected ladders", name)
+		}
+		return nil, nil
+	}
+	want, err := strconv.Atoi(n)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	for _, l := range ladders {
+		if steps(l) != want {
+			return nil, fmt.Errorf("%s: ladder %q does not have %d steps", name, l, want)
+		}
+	}
+	return ladders, nil
+}
+
+// steps returns the number of steps in the ladder l.
+func steps(l string) int {
+	return len(strings.Fields(l)) - 1
+}
+
+// contains returns whether the sorted ladders contain l.
+func contains(ladders []string, l string) bool {
+	i := sort.SearchStrings(ladders, l)
+	return i < len(ladders) && ladders[i] == l
+}
@@ -1,5 +1,7 @@
 module gonum.org/website/static/code/word_ladders
 
-go 1.15
+go 1.22
 
-require gonum.org/v1/gonum v0.8.1
+require gonum.org/v1/gonum v0.15.1
+
+require golang.org/x/exp v0.0.0-20231110203233-9a3e6036ecaa // indirect
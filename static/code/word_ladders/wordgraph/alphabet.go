@@ -0,0 +1,72 @@
+package wordgraph
+
+import (
+	"sort"
+	"strings"
+)
+
+// alphabet is an ordered set of the letters that may be used in words.
+type alphabet struct {
+	letters []rune
+
+	// inferred indicates that the alphabet is the
+	// set of letters used by the admitted words.
+	inferred bool
+}
+
+// newAlphabet returns an alphabet holding the lower case of the provided
+// letters. If letters is empty, the returned alphabet is inferred from
+// the words it admits.
+func newAlphabet(letters string) alphabet {
+	if letters == "" {
+		return alphabet{inferred: true}
+	}
+	var a alphabet
+	for _, c := range strings.ToLower(letters) {
+		a.add(c)
+	}
+	return a
+}
+
+// admit returns whether all the letters of the word are in the alphabet.
+// An inferred alphabet admits all words.
+func (a *alphabet) admit(word string) bool {
+	if a.inferred {
+		return true
+	}
+	for _, c := range word {
+		if !a.has(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// add adds c to the alphabet.
+func (a *alphabet) add(c rune) {
+	i := sort.Search(len(a.letters), func(i int) bool { return a.letters[i] >= c })
+	if i < len(a.letters) && a.letters[i] == c {
+		return
+	}
+	a.letters = append(a.letters, 0)
+	copy(a.letters[i+1:], a.letters[i:])
+	a.letters[i] = c
+}
+
+// has returns whether c is in the alphabet.
+func (a *alphabet) has(c rune) bool {
+	i := sort.Search(len(a.letters), func(i int) bool { return a.letters[i] >= c })
+	return i < len(a.letters) && a.letters[i] == c
+}
+
+// Letters returns the ordered set of letters used by the words in the
+// words map.
+func Letters(words map[string]int64) []rune {
+	var a alphabet
+	for w := range words {
+		for _, c := range w {
+			a.add(c)
+		}
+	}
+	return a.letters
+}
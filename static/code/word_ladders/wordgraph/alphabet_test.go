@@ -0,0 +1,81 @@
+package wordgraph
+
+import (
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+var alphabetTests = []struct {
+	letters string
+	admit   []string
+	reject  []string
+}{
+	{
+		letters: "",
+		admit:   []string{"cold", "café", "naïve", "ωμέγα"},
+	},
+	{
+		letters: "acdefloprw",
+		admit:   []string{"cold", "cafe", "ward", "a"},
+		reject:  []string{"café", "warm", "naïve"},
+	},
+	{
+		letters: "ACEFÉ",
+		admit:   []string{"café", "face", "cafe"},
+		reject:  []string{"cold", "naïve"},
+	},
+	{
+		letters: "αβγιμέωο",
+		admit:   []string{"ωμέγα", "βέβαιο"},
+		reject:  []string{"cold", "ωμεγα"},
+	},
+}
+
+func TestAlphabet(t *testing.T) {
+	for _, test := range alphabetTests {
+		a := newAlphabet(test.letters)
+		for _, w := range test.admit {
+			if !a.admit(w) {
+				t.Errorf("unexpected rejection of %q by alphabet %q", w, test.letters)
+			}
+		}
+		for _, w := range test.reject {
+			if a.admit(w) {
+				t.Errorf("unexpected admission of %q by alphabet %q", w, test.letters)
+			}
+		}
+
+		for _, kind := range []Kind{Eager, Lazy} {
+			g := New(kind, 0, Alphabet(test.letters))
+			for _, w := range test.reject {
+				g.Include(w)
+			}
+			for _, w := range test.admit {
+				g.Include(w)
+			}
+			for _, w := range test.reject {
+				if g.NodeFor(w) != nil {
+					t.Errorf("unexpected node for %q in graph with alphabet %q", w, test.letters)
+				}
+			}
+			for _, w := range test.admit {
+				if g.NodeFor(w) == nil {
+					t.Errorf("missing node for %q in graph with alphabet %q", w, test.letters)
+				}
+			}
+			if n := len(graph.NodesOf(g.Nodes())); n != len(test.admit) {
+				t.Errorf("unexpected number of nodes in graph with alphabet %q: got:%d want:%d", test.letters, n, len(test.admit))
+			}
+		}
+	}
+}
+
+func TestLetters(t *testing.T) {
+	got := Letters(map[string]int64{"café": 0, "cafe": 1, "ωμέγα": 2})
+	want := []rune{'a', 'c', 'e', 'f', 'é', 'έ', 'α', 'γ', 'μ', 'ω'}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected letters:\ngot: %q\nwant:%q", got, want)
+	}
+}
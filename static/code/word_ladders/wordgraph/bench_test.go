@@ -0,0 +1,212 @@
+package wordgraph
+
+import (
+	"flag"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/path"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var (
+	benchWords  = flag.Int("bench.words", 2000, "number of words in the synthetic benchmark dictionary")
+	benchLength = flag.Int("bench.length", 4, "length of words in the synthetic benchmark dictionary")
+)
+
+// benchLetters is the alphabet of synthetic dictionaries. It is
+// restricted to common letters so that words are well connected.
+const benchLetters = "etaoinshrdlu"
+
+// synthDictionary returns n distinct random words of the given length
+// using letters chosen from benchLetters. It panics if there are fewer
+// than n possible words.
+func synthDictionary(n, length int, seed int64) []string {
+	possible := 1
+	for i := 0; i < length && possible < n; i++ {
+		possible *= len(benchLetters)
+	}
+	if possible < n {
+		panic("wordgraph: too few possible words for synthetic dictionary")
+	}
+	rnd := rand.New(rand.NewSource(seed))
+	seen := make(map[string]bool, n)
+	words := make([]string, 0, n)
+	b := make([]byte, length)
+	for len(words) < n {
+		for i := range b {
+			b[i] = benchLetters[rnd.Intn(len(benchLetters))]
+		}
+		w := string(b)
+		if seen[w] {
+			continue
+		}
+		seen[w] = true
+		words = append(words, w)
+	}
+	return words
+}
+
+var benchKinds = []struct {
+	name string
+	kind Kind
+}{
+	{name: "Eager", kind: Eager},
+	{name: "Lazy", kind: Lazy},
+	{name: "LazySlice", kind: LazySlice},
+}
+
+// benchGraph returns a graph of the given kind holding the words, and the
+// first word and the word furthest from it, to be used as a doublet.
+func benchGraph(kind Kind, words []string) (g Graph, first, last graph.Node) {
+	g = New(kind, *benchLength)
+	for _, w := range words {
+		g.Include(w)
+	}
+	nodes, indexOf := sortedNodes(g)
+	b := newLayers(len(nodes))
+	b.walk(g, nodes, indexOf, 0)
+	far := 0
+	for i, d := range b.depth {
+		if d > b.depth[far] {
+			far = i
+		}
+	}
+	return g, nodes[0], nodes[far]
+}
+
+func BenchmarkBuild(b *testing.B) {
+	words := synthDictionary(*benchWords, *benchLength, 1)
+	for _, bk := range benchKinds {
+		b.Run(bk.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				g := New(bk.kind, *benchLength)
+				for _, w := range words {
+					g.Include(w)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkNaiveBuild builds the graph of words in the same way as
+// words-0, looking up the neighbours of each word in a map of words.
+func BenchmarkNaiveBuild(b *testing.B) {
+	words := synthDictionary(*benchWords, *benchLength, 1)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ids := make(map[string]int64, len(words))
+		for _, w := range words {
+			ids[w] = int64(len(ids))
+		}
+		letters := Letters(ids)
+		g := simple.NewUndirectedGraph()
+		for u, uid := range ids {
+			for _, v := range Neighbours(u, ids, letters) {
+				g.SetEdge(simple.Edge{F: simple.Node(uid), T: simple.Node(ids[v])})
+			}
+		}
+	}
+}
+
+func BenchmarkNeighbours(b *testing.B) {
+	words := synthDictionary(*benchWords, *benchLength, 1)
+	b.Run("Naive", func(b *testing.B) {
+		ids := make(map[string]int64, len(words))
+		for i, w := range words {
+			ids[w] = int64(i)
+		}
+		letters := Letters(ids)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, w := range words {
+				Neighbours(w, ids, letters)
+			}
+		}
+	})
+	for _, bk := range benchKinds {
+		g, _, _ := benchGraph(bk.kind, words)
+		nodes := graph.NodesOf(g.Nodes())
+		b.Run(bk.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				for _, u := range nodes {
+					for it := g.From(u.ID()); it.Next(); {
+					}
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkLadder(b *testing.B) {
+	words := synthDictionary(*benchWords, *benchLength, 1)
+	for _, bk := range benchKinds {
+		g, first, last := benchGraph(bk.kind, words)
+		b.Run(bk.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				pth := path.DijkstraFrom(first, g)
+				pth.To(last.ID())
+			}
+		})
+	}
+}
+
+func BenchmarkAllLadders(b *testing.B) {
+	words := synthDictionary(*benchWords, *benchLength, 1)
+	for _, bk := range benchKinds {
+		g, first, last := benchGraph(bk.kind, words)
+		b.Run(bk.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				pth := path.DijkstraAllFrom(first, g)
+				pth.AllTo(last.ID())
+			}
+		})
+	}
+}
+
+// BenchmarkAllPaths finds the shortest paths between all pairs of words
+// with path.DijkstraAllPaths, as the post does for the longest and widest
+// ladders, for comparison with BenchmarkLongest and BenchmarkWidest.
+func BenchmarkAllPaths(b *testing.B) {
+	words := synthDictionary(*benchWords, *benchLength, 1)
+	for _, bk := range benchKinds {
+		g, _, _ := benchGraph(bk.kind, words)
+		b.Run(bk.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				path.DijkstraAllPaths(g)
+			}
+		})
+	}
+}
+
+func BenchmarkLongest(b *testing.B) {
+	words := synthDictionary(*benchWords, *benchLength, 1)
+	for _, bk := range benchKinds {
+		g, _, _ := benchGraph(bk.kind, words)
+		b.Run(bk.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				Longest(g, 1)
+			}
+		})
+	}
+}
+
+func BenchmarkWidest(b *testing.B) {
+	words := synthDictionary(*benchWords, *benchLength, 1)
+	for _, bk := range benchKinds {
+		g, _, _ := benchGraph(bk.kind, words)
+		b.Run(bk.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				Widest(g, 1)
+			}
+		})
+	}
+}
@@ -0,0 +1,509 @@
+package wordgraph
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/iterator"
+)
+
+// The cache file format holds the Hamming distance word graph for each
+// word length in a dictionary. All integers are little-endian.
+//
+//	header:
+//		magic    [4]byte  "WLGC"
+//		version  uint32
+//		checksum [32]byte SHA-256 of the source dictionary
+//		sections uint32
+//		reserved uint32
+//	section table, one entry per word length:
+//		length     uint32 letters in each word
+//		nodes      uint32
+//		neighbours uint32 total length of the adjacency lists
+//		reserved   uint32
+//		offset     uint64 start of the section data, 8 byte aligned
+//	section data:
+//		word offsets      [nodes+1]uint32 into the word bytes
+//		adjacency offsets [nodes+1]uint32 into the neighbours
+//		neighbours        [neighbours]uint32 sorted node IDs
+//		word bytes        sorted words
+//
+// Node IDs are the indexes of words in the sorted word list of a section.
+const (
+	cacheMagic   = "WLGC"
+	cacheVersion = 1
+
+	cacheHeaderSize  = 48
+	cacheSectionSize = 24
+)
+
+// cacheHeader is the header of a cache file.
+type cacheHeader struct {
+	Magic    [4]byte
+	Version  uint32
+	Checksum [sha256.Size]byte
+	Sections uint32
+	_        uint32
+}
+
+// cacheSection is an entry in the section table of a cache file.
+type cacheSection struct {
+	Length     uint32
+	Nodes      uint32
+	Neighbours uint32
+	_          uint32
+	Offset     uint64
+}
+
+// ErrStaleCache is returned by OpenCache when the cache was not
+// built from the given dictionary.
+var ErrStaleCache = errors.New("wordgraph: cache is stale")
+
+// Checksum returns the checksum of a dictionary that is recorded in
+// a cache built from it.
+func Checksum(dict []byte) [sha256.Size]byte {
+	return sha256.Sum256(dict)
+}
+
+// WriteCache writes a cache of the Hamming distance word graphs for each
+// word length of the words in the dictionary dict to w. Words are included
+// as for a word graph with an inferred alphabet.
+func WriteCache(w io.Writer, dict []byte) error {
+	byLength := make(map[int][]string)
+	seen := make(map[string]bool)
+	for _, word := range strings.Split(string(dict), "\n") {
+		word = strings.ToLower(strings.TrimSuffix(word, "\r"))
+		if !IsWord(word) || seen[word] {
+			continue
+		}
+		seen[word] = true
+		n := utf8.RuneCountInString(word)
+		byLength[n] = append(byLength[n], word)
+	}
+	lengths := make([]int, 0, len(byLength))
+	for n := range byLength {
+		lengths = append(lengths, n)
+	}
+	sort.Ints(lengths)
+
+	var data bytes.Buffer
+	table := make([]cacheSection, 0, len(lengths))
+	base := cacheHeaderSize + len(lengths)*cacheSectionSize
+	for _, n := range lengths {
+		words := byLength[n]
+		sort.Strings(words)
+
+		// Build a lazy graph with IDs in sorted order to
+		// find the neighbours of each word.
+		g := newLazyGraph(n, false, config{})
+		for _, word := range words {
+			g.Include(word)
+		}
+		wordOffsets := make([]uint32, 0, len(words)+1)
+		adjOffsets := make([]uint32, 0, len(words)+1)
+		var (
+			adj      []uint32
+			wordData []byte
+		)
+		for id, word := range words {
+			wordOffsets = append(wordOffsets, uint32(len(wordData)))
+			wordData = append(wordData, word...)
+			adjOffsets = append(adjOffsets, uint32(len(adj)))
+			start := len(adj)
+			for it := newNeighbours(g.index, int64(id), false); it.Next(); {
+				adj = append(adj, uint32(it.Node().ID()))
+			}
+			sort.Slice(adj[start:], func(i, j int) bool { return adj[start+i] < adj[start+j] })
+		}
+		wordOffsets = append(wordOffsets, uint32(len(wordData)))
+		adjOffsets = append(adjOffsets, uint32(len(adj)))
+		if len(wordData) > math.MaxUint32 || len(adj) > math.MaxUint32 {
+			return fmt.Errorf("wordgraph: too many words of length %d to cache", n)
+		}
+
+		for data.Len()%8 != 0 {
+			data.WriteByte(0)
+		}
+		table = append(table, cacheSection{
+			Length:     uint32(n),
+			Nodes:      uint32(len(words)),
+			Neighbours: uint32(len(adj)),
+			Offset:     uint64(base + data.Len()),
+		})
+		for _, s := range [][]uint32{wordOffsets, adjOffsets, adj} {
+			binary.Write(&data, binary.LittleEndian, s)
+		}
+		data.Write(wordData)
+	}
+
+	header := cacheHeader{
+		Version:  cacheVersion,
+		Checksum: Checksum(dict),
+		Sections: uint32(len(lengths)),
+	}
+	copy(header.Magic[:], cacheMagic)
+	for _, v := range []interface{}{header, table} {
+		err := binary.Write(w, binary.LittleEndian, v)
+		if err != nil {
+			return err
+		}
+	}
+	_, err := w.Write(data.Bytes())
+	return err
+}
+
+// Cache is a memory-mapped word graph cache.
+type Cache struct {
+	data     []byte
+	checksum [sha256.Size]byte
+	graphs   map[int]*CachedGraph
+}
+
+// OpenCache memory-maps the named cache file. If the cache was not built
+// from the dictionary with the given checksum, OpenCache returns
+// ErrStaleCache.
+func OpenCache(name string, checksum [sha256.Size]byte) (*Cache, error) {
+	data, err := mmap(name)
+	if err != nil {
+		return nil, err
+	}
+	c, err := newCache(data)
+	if err == nil && c.checksum != checksum {
+		err = ErrStaleCache
+	}
+	if err != nil {
+		munmap(data)
+		return nil, err
+	}
+	return c, nil
+}
+
+// LoadCache returns the cache in the named file for the dictionary dict,
+// first building the cache if the file does not exist, or holds a stale
+// or invalid cache.
+func LoadCache(name string, dict []byte) (*Cache, error) {
+	sum := Checksum(dict)
+	c, err := OpenCache(name, sum)
+	if err == nil {
+		return c, nil
+	}
+	var invalid cacheError
+	if !errors.Is(err, os.ErrNotExist) && !errors.Is(err, ErrStaleCache) && !errors.As(err, &invalid) {
+		return nil, err
+	}
+
+	// Write the cache to a temporary file and move it into
+	// place so that readers never see a partial cache.
+	f, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	err = WriteCache(f, dict)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	err = f.Close()
+	if err != nil {
+		return nil, err
+	}
+	err = os.Chmod(f.Name(), 0o664)
+	if err != nil {
+		return nil, err
+	}
+	err = os.Rename(f.Name(), name)
+	if err != nil {
+		return nil, err
+	}
+	return OpenCache(name, sum)
+}
+
+// cacheError is an error in the format of a cache.
+type cacheError string
+
+func (e cacheError) Error() string { return "wordgraph: invalid cache: " + string(e) }
+
+// newCache returns a Cache holding the graphs in data after checking
+// that the cache is valid.
+func newCache(data []byte) (*Cache, error) {
+	r := bytes.NewReader(data)
+	var header cacheHeader
+	err := binary.Read(r, binary.LittleEndian, &header)
+	if err != nil || string(header.Magic[:]) != cacheMagic {
+		return nil, cacheError("bad magic")
+	}
+	if header.Version != cacheVersion {
+		return nil, cacheError(fmt.Sprintf("unsupported version %d", header.Version))
+	}
+	if uint64(len(data)) < cacheHeaderSize+uint64(header.Sections)*cacheSectionSize {
+		return nil, cacheError("truncated section table")
+	}
+	table := make([]cacheSection, header.Sections)
+	err = binary.Read(r, binary.LittleEndian, table)
+	if err != nil {
+		return nil, cacheError("truncated section table")
+	}
+
+	c := &Cache{data: data, checksum: header.Checksum, graphs: make(map[int]*CachedGraph)}
+	start := cacheHeaderSize + uint64(len(table))*cacheSectionSize
+	for _, s := range table {
+		n := int(s.Length)
+		nodes := uint64(s.Nodes)
+		neighbours := uint64(s.Neighbours)
+		offset := s.Offset
+
+		// Check the offset before using it so that the
+		// end of the section can not overflow. The node
+		// and neighbour counts are 32 bit, so their sum
+		// can not.
+		if offset < start || offset > uint64(len(data)) {
+			return nil, cacheError(fmt.Sprintf("section offset out of range for length %d", n))
+		}
+		if 2*(nodes+1)+neighbours > (uint64(len(data))-offset)/4 {
+			return nil, cacheError(fmt.Sprintf("truncated section for length %d", n))
+		}
+		end := offset + 4*(2*(nodes+1)+neighbours)
+		g := &CachedGraph{
+			n:          n,
+			nodes:      int64(nodes),
+			wordOffs:   data[offset : offset+4*(nodes+1)],
+			adjOffs:    data[offset+4*(nodes+1) : offset+8*(nodes+1)],
+			neighbours: data[offset+8*(nodes+1) : end],
+		}
+		wordBytes := uint64(g.wordOff(g.nodes))
+		if end+wordBytes > uint64(len(data)) || uint64(g.adjOff(g.nodes)) != neighbours {
+			return nil, cacheError(fmt.Sprintf("inconsistent section for length %d", n))
+		}
+		g.words = data[end : end+wordBytes]
+		if msg := g.check(); msg != "" {
+			return nil, cacheError(fmt.Sprintf("%s in section for length %d", msg, n))
+		}
+		c.graphs[n] = g
+	}
+	return c, nil
+}
+
+// Graph returns the graph of words with n letters held by the cache.
+// If the dictionary held no words of that length, the graph is empty.
+func (c *Cache) Graph(n int) *CachedGraph {
+	g, ok := c.graphs[n]
+	if !ok {
+		return &CachedGraph{n: n}
+	}
+	return g
+}
+
+// Close unmaps the cache. Graphs returned by the cache must not be used
+// after Close has been called.
+func (c *Cache) Close() error {
+	c.graphs = nil
+	return munmap(c.data)
+}
+
+// CachedGraph is a read-only graph of distance-1 word paths held in a
+// memory-mapped cache. Edges have unit weight.
+type CachedGraph struct {
+	n     int
+	nodes int64
+
+	// wordOffs, adjOffs and neighbours are
+	// little-endian uint32 arrays.
+	wordOffs   []byte
+	adjOffs    []byte
+	neighbours []byte
+	words      []byte
+}
+
+// check returns a description of the first inconsistency in the offsets,
+// words and neighbours of g, or the empty string if they are consistent.
+// Word offsets must increase, adjacency offsets must not decrease so that
+// they stay within the neighbours, words must be sorted for NodeFor to
+// find them, and neighbours must be IDs of nodes in g.
+func (g *CachedGraph) check() string {
+	if g.wordOff(0) != 0 || g.adjOff(0) != 0 {
+		return "non-zero initial offset"
+	}
+	for id := int64(0); id < g.nodes; id++ {
+		if g.wordOff(id+1) <= g.wordOff(id) {
+			return fmt.Sprintf("word offset not increasing at node %d", id+1)
+		}
+		if g.adjOff(id+1) < g.adjOff(id) {
+			return fmt.Sprintf("adjacency offset decreasing at node %d", id+1)
+		}
+	}
+	for id := int64(1); id < g.nodes; id++ {
+		if g.word(id) <= g.word(id-1) {
+			return fmt.Sprintf("word not sorted at node %d", id)
+		}
+	}
+	for i := 0; i < len(g.neighbours); i += 4 {
+		if nid := int64(binary.LittleEndian.Uint32(g.neighbours[i:])); nid >= g.nodes {
+			return fmt.Sprintf("neighbour ID %d out of range", nid)
+		}
+	}
+	return ""
+}
+
+func (g *CachedGraph) wordOff(id int64) uint32 { return binary.LittleEndian.Uint32(g.wordOffs[4*id:]) }
+func (g *CachedGraph) adjOff(id int64) uint32  { return binary.LittleEndian.Uint32(g.adjOffs[4*id:]) }
+
+// word returns the word with the given ID.
+func (g *CachedGraph) word(id int64) string {
+	return string(g.words[g.wordOff(id):g.wordOff(id+1)])
+}
+
+// adjacent returns the neighbours of the node with the given ID.
+func (g *CachedGraph) adjacent(id int64) []byte {
+	return g.neighbours[4*g.adjOff(id) : 4*g.adjOff(id+1)]
+}
+
+// Include implements the Graph Include method. A CachedGraph is read-only,
+// so words that are not already in the graph are not included.
+func (g *CachedGraph) Include(word string) {}
+
+// NodeFor returns a graph.Node representing the word, or nil if the word
+// is not in the graph.
+func (g *CachedGraph) NodeFor(word string) graph.Node {
+	id := sort.Search(int(g.nodes), func(i int) bool { return g.word(int64(i)) >= word })
+	if int64(id) == g.nodes || g.word(int64(id)) != word {
+		return nil
+	}
+	return node{word: word, id: int64(id)}
+}
+
+// Node implements the graph.Graph Node method.
+func (g *CachedGraph) Node(id int64) graph.Node {
+	if uint64(id) >= uint64(g.nodes) {
+		return nil
+	}
+	return node{word: g.word(id), id: id}
+}
+
+// Nodes implements the graph.Graph Nodes method.
+func (g *CachedGraph) Nodes() graph.Nodes {
+	if g.nodes == 0 {
+		return graph.Empty
+	}
+	nodes := make([]graph.Node, g.nodes)
+	for id := range nodes {
+		nodes[id] = node{word: g.word(int64(id)), id: int64(id)}
+	}
+	return iterator.NewOrderedNodes(nodes)
+}
+
+// From implements the graph.Graph From method.
+func (g *CachedGraph) From(id int64) graph.Nodes {
+	if uint64(id) >= uint64(g.nodes) {
+		return graph.Empty
+	}
+	adj := g.adjacent(id)
+	if len(adj) == 0 {
+		return graph.Empty
+	}
+	return &cachedNeighbours{g: g, adj: adj, i: -1}
+}
+
+// HasEdgeBetween implements the graph.Graph HasEdgeBetween method.
+func (g *CachedGraph) HasEdgeBetween(uid, vid int64) bool {
+	if uid == vid || uint64(uid) >= uint64(g.nodes) || uint64(vid) >= uint64(g.nodes) {
+		return false
+	}
+	adj := g.adjacent(uid)
+	n := len(adj) / 4
+	i := sort.Search(n, func(i int) bool { return int64(binary.LittleEndian.Uint32(adj[4*i:])) >= vid })
+	return i < n && int64(binary.LittleEndian.Uint32(adj[4*i:])) == vid
+}
+
+// Edge implements the graph.Graph Edge method.
+func (g *CachedGraph) Edge(uid, vid int64) graph.Edge {
+	if !g.HasEdgeBetween(uid, vid) {
+		return nil
+	}
+	return edge{f: node{word: g.word(uid), id: uid}, t: node{word: g.word(vid), id: vid}}
+}
+
+// EdgeBetween implements the graph.Undirected EdgeBetween method.
+func (g *CachedGraph) EdgeBetween(uid, vid int64) graph.Edge {
+	return g.Edge(uid, vid)
+}
+
+// HeuristicCost implements the path.HeuristicCoster interface. It returns
+// the Hamming distance between the words of x and y.
+func (g *CachedGraph) HeuristicCost(x, y graph.Node) float64 {
+	return float64(HammingDistance(g.word(x.ID()), g.word(y.ID())))
+}
+
+// Weight implements the graph.Weighted Weight method.
+func (g *CachedGraph) Weight(xid, yid int64) (w float64, ok bool) {
+	if xid == yid {
+		return 0, true
+	}
+	if !g.HasEdgeBetween(xid, yid) {
+		return math.Inf(1), false
+	}
+	return 1, true
+}
+
+// WeightedEdge implements the graph.Weighted WeightedEdge method.
+func (g *CachedGraph) WeightedEdge(uid, vid int64) graph.WeightedEdge {
+	if !g.HasEdgeBetween(uid, vid) {
+		return nil
+	}
+	return weightedEdge{edge: edge{f: node{word: g.word(uid), id: uid}, t: node{word: g.word(vid), id: vid}}, w: 1}
+}
+
+// WeightedEdgeBetween implements the graph.WeightedUndirected
+// WeightedEdgeBetween method.
+func (g *CachedGraph) WeightedEdgeBetween(xid, yid int64) graph.WeightedEdge {
+	return g.WeightedEdge(xid, yid)
+}
+
+// cachedNeighbours implements the graph.Nodes interface. It is an
+// iterator over the neighbours of a node held in a cache.
+type cachedNeighbours struct {
+	g   *CachedGraph
+	adj []byte
+	i   int
+}
+
+// Len implements the graph.Nodes Len method.
+func (it *cachedNeighbours) Len() int {
+	if it.i >= len(it.adj)/4 {
+		return 0
+	}
+	return len(it.adj)/4 - (it.i + 1)
+}
+
+// Next implements the graph.Nodes Next method.
+func (it *cachedNeighbours) Next() bool {
+	if it.i+1 >= len(it.adj)/4 {
+		it.i = len(it.adj) / 4
+		return false
+	}
+	it.i++
+	return true
+}
+
+// Node implements the graph.Nodes Node method.
+func (it *cachedNeighbours) Node() graph.Node {
+	if it.i < 0 || it.i >= len(it.adj)/4 {
+		return nil
+	}
+	id := int64(binary.LittleEndian.Uint32(it.adj[4*it.i:]))
+	return node{word: it.g.word(id), id: id}
+}
+
+// Reset implements the graph.Nodes Reset method.
+func (it *cachedNeighbours) Reset() { it.i = -1 }
@@ -0,0 +1,214 @@
+package wordgraph
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/testgraph"
+)
+
+// testDictionary returns testWords as the contents of a dictionary file.
+func testDictionary() []byte {
+	return []byte(strings.Join(testWords, "\n") + "\n")
+}
+
+func TestCache(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "words.cache")
+	c, err := LoadCache(name, testDictionary())
+	if err != nil {
+		t.Fatalf("failed to load cache: %v", err)
+	}
+	defer c.Close()
+
+	for n := 1; n <= 6; n++ {
+		want := New(Lazy, n)
+		for _, w := range testWords {
+			want.Include(w)
+		}
+		got := c.Graph(n)
+
+		nodes := nodeWords(graph.NodesOf(want.Nodes()))
+		if gotNodes := nodeWords(graph.NodesOf(got.Nodes())); !reflect.DeepEqual(gotNodes, nodes) {
+			t.Errorf("unexpected nodes for length %d:\ngot: %v\nwant:%v", n, gotNodes, nodes)
+		}
+		for _, w := range nodes {
+			u := got.NodeFor(w)
+			if u == nil {
+				t.Errorf("missing node for %q", w)
+				continue
+			}
+			wantAdj := nodeWords(graph.NodesOf(want.From(want.NodeFor(w).ID())))
+			gotAdj := nodeWords(graph.NodesOf(got.From(u.ID())))
+			if !reflect.DeepEqual(gotAdj, wantAdj) {
+				t.Errorf("unexpected neighbours of %q:\ngot: %v\nwant:%v", w, gotAdj, wantAdj)
+			}
+			for _, v := range gotAdj {
+				if !got.HasEdgeBetween(u.ID(), got.NodeFor(v).ID()) {
+					t.Errorf("missing edge between %q and %q", w, v)
+				}
+			}
+		}
+	}
+	if got := c.Graph(2).NodeFor("cat"); got != nil {
+		t.Errorf("unexpected node for word of wrong length: %v", got)
+	}
+}
+
+func TestLoadCacheRebuild(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "words.cache")
+	c, err := LoadCache(name, []byte("cold\ncord\n"))
+	if err != nil {
+		t.Fatalf("failed to load cache: %v", err)
+	}
+	c.Close()
+
+	dict := []byte("cold\ncord\ncard\n")
+	_, err = OpenCache(name, Checksum(dict))
+	if !errors.Is(err, ErrStaleCache) {
+		t.Errorf("unexpected error opening stale cache: got:%v want:%v", err, ErrStaleCache)
+	}
+	c, err = LoadCache(name, dict)
+	if err != nil {
+		t.Fatalf("failed to rebuild stale cache: %v", err)
+	}
+	if c.Graph(4).NodeFor("card") == nil {
+		t.Error("rebuilt cache is missing new word")
+	}
+	c.Close()
+
+	err = os.WriteFile(name, []byte("not a cache"), 0o664)
+	if err != nil {
+		t.Fatalf("failed to write invalid cache: %v", err)
+	}
+	c, err = LoadCache(name, dict)
+	if err != nil {
+		t.Fatalf("failed to rebuild invalid cache: %v", err)
+	}
+	if c.Graph(4).NodeFor("card") == nil {
+		t.Error("rebuilt cache is missing word")
+	}
+	c.Close()
+}
+
+func TestInvalidCache(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteCache(&buf, testDictionary())
+	if err != nil {
+		t.Fatalf("failed to write cache: %v", err)
+	}
+	valid := buf.Bytes()
+
+	version := append([]byte(nil), valid...)
+	binary.LittleEndian.PutUint32(version[4:], cacheVersion+1)
+
+	// Find the data of the first section with edges
+	// to corrupt its offsets and neighbours.
+	var entry, offset, nodes, count uint64
+	for i := uint64(0); ; i++ {
+		entry = cacheHeaderSize + i*cacheSectionSize
+		if count = uint64(binary.LittleEndian.Uint32(valid[entry+8:])); count != 0 {
+			nodes = uint64(binary.LittleEndian.Uint32(valid[entry+4:]))
+			offset = binary.LittleEndian.Uint64(valid[entry+16:])
+			break
+		}
+	}
+	corrupt := func(at uint64, v uint32) []byte {
+		data := append([]byte(nil), valid...)
+		binary.LittleEndian.PutUint32(data[at:], v)
+		return data
+	}
+	moved := func(offset uint64, nodes, neighbours uint32) []byte {
+		data := corrupt(entry+4, nodes)
+		binary.LittleEndian.PutUint32(data[entry+8:], neighbours)
+		binary.LittleEndian.PutUint64(data[entry+16:], offset)
+		return data
+	}
+	wordOffs := offset
+	adjOffs := offset + 4*(nodes+1)
+	neighbours := offset + 8*(nodes+1)
+	words := neighbours + 4*count
+
+	for _, test := range []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: nil},
+		{name: "magic", data: append([]byte("WLGX"), valid[4:]...)},
+		{name: "version", data: version},
+		{name: "table", data: valid[:cacheHeaderSize+cacheSectionSize/2]},
+		{name: "section", data: valid[:len(valid)-1]},
+		{name: "initial offset", data: corrupt(wordOffs, 1)},
+		{name: "word offset", data: corrupt(wordOffs+4, 0)},
+		{name: "adjacency offset", data: corrupt(adjOffs+4, 0xfffffff0)},
+		{name: "neighbour ID", data: corrupt(neighbours, 0xfffffff0)},
+		{name: "unsorted words", data: corrupt(words, 0xffffffff)},
+		{name: "overflowing offset", data: moved(^uint64(0)-3, 0, 0)},
+		{name: "offset past end", data: moved(uint64(len(valid))+4, 0, 0)},
+		{name: "offset into table", data: moved(0, 0, 0)},
+		{name: "node count", data: moved(offset, 0xffffffff, uint32(count))},
+		{name: "neighbour count", data: moved(offset, uint32(nodes), 0xffffffff)},
+	} {
+		_, err := newCache(test.data)
+		var invalid cacheError
+		if !errors.As(err, &invalid) {
+			t.Errorf("unexpected error for invalid %s: %v", test.name, err)
+		}
+	}
+}
+
+func TestCacheConformance(t *testing.T) {
+	b := func(nodes []graph.Node, _ []testgraph.WeightedLine, _, _ float64) (graph.Graph, []graph.Node, []testgraph.Edge, float64, float64, bool) {
+		var dict []byte
+		if len(nodes) != 0 {
+			dict = testDictionary()
+		}
+		var buf bytes.Buffer
+		err := WriteCache(&buf, dict)
+		if err != nil {
+			panic(err)
+		}
+		c, err := newCache(buf.Bytes())
+		if err != nil {
+			panic(err)
+		}
+		g := c.Graph(4)
+		words := graph.NodesOf(g.Nodes())
+		var edges []testgraph.Edge
+		for i, u := range words {
+			for _, v := range words[i+1:] {
+				if joined(u.(node).word, v.(node).word, false) {
+					edges = append(edges, edge{f: u.(node), t: v.(node)})
+				}
+			}
+		}
+		return g, words, edges, 0, math.Inf(1), true
+	}
+
+	// Cached graphs return graph.Empty for
+	// words without neighbours.
+	const usesEmpty = true
+
+	t.Run("EdgeExistence", func(t *testing.T) {
+		testgraph.EdgeExistence(t, b, reversesEdges)
+	})
+	t.Run("NodeExistence", func(t *testing.T) {
+		testgraph.NodeExistence(t, b)
+	})
+	t.Run("ReturnAdjacentNodes", func(t *testing.T) {
+		testgraph.ReturnAdjacentNodes(t, b, usesEmpty, reversesEdges)
+	})
+	t.Run("ReturnAllNodes", func(t *testing.T) {
+		testgraph.ReturnAllNodes(t, b, usesEmpty)
+	})
+	t.Run("ReturnNodeSlice", func(t *testing.T) {
+		testgraph.ReturnNodeSlice(t, b, usesEmpty)
+	})
+}
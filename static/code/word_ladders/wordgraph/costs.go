@@ -0,0 +1,204 @@
+package wordgraph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Gap is the letter used in a Costs table to specify the cost of
+// inserting or deleting a letter.
+const Gap = '-'
+
+// Costs is a table of the costs of single letter edits. Costs are
+// symmetric, so the cost of substituting a for b is the same as the
+// cost of substituting b for a. Edits that are not in the table have
+// unit cost.
+type Costs struct {
+	costs map[[2]rune]float64
+}
+
+// NewCosts returns a new Costs table with all edits having unit cost.
+func NewCosts() *Costs {
+	return &Costs{costs: make(map[[2]rune]float64)}
+}
+
+// Set sets the cost of substituting the letter a for b. If either a or b
+// is Gap, Set sets the cost of inserting or deleting the other letter.
+// Set will panic if cost is negative or NaN.
+func (c *Costs) Set(a, b rune, cost float64) {
+	if !(cost >= 0) {
+		panic("wordgraph: invalid edit cost")
+	}
+	c.costs[pair(a, b)] = cost
+}
+
+// Cost returns the cost of substituting the letter a for b. If either
+// a or b is Gap, Cost returns the cost of inserting or deleting the
+// other letter.
+func (c *Costs) Cost(a, b rune) float64 {
+	if a == b {
+		return 0
+	}
+	cost, ok := c.costs[pair(a, b)]
+	if !ok {
+		return 1
+	}
+	return cost
+}
+
+// pair returns the key for the edit between a and b.
+func pair(a, b rune) [2]rune {
+	a = unicode.ToLower(a)
+	b = unicode.ToLower(b)
+	if b < a {
+		a, b = b, a
+	}
+	return [2]rune{a, b}
+}
+
+// min returns the minimum cost of any edit in the table.
+func (c *Costs) min() float64 {
+	min := 1.0
+	for _, cost := range c.costs {
+		min = math.Min(min, cost)
+	}
+	return min
+}
+
+// between returns the cost of the single letter edit that transforms
+// the word u into v.
+func (c *Costs) between(u, v string) float64 {
+	ru := []rune(u)
+	rv := []rune(v)
+	if len(rv) < len(ru) {
+		ru, rv = rv, ru
+	}
+	i := 0
+	for i < len(ru) && ru[i] == rv[i] {
+		i++
+	}
+	if len(ru) == len(rv) {
+		return c.Cost(ru[i], rv[i])
+	}
+	return c.Cost(Gap, rv[i])
+}
+
+// ReadCosts returns a Costs table read from r. Each line of the input
+// holds a pair of letters and the cost of substituting one for the other,
+// separated by white space. Either letter may be Gap to give the cost of
+// inserting or deleting the other. Blank lines and lines starting with #
+// are ignored. For example,
+//
+//	# Vowels are cheap to substitute.
+//	a e 0.5
+//	# Plurals are cheap to form.
+//	- s 0.5
+func ReadCosts(r io.Reader) (*Costs, error) {
+	c := NewCosts()
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("wordgraph: line %d: expected two letters and a cost", line)
+		}
+		var letters [2]rune
+		for i, f := range fields[:2] {
+			l, n := utf8.DecodeRuneInString(f)
+			if n != len(f) || (l != Gap && !unicode.IsLetter(l)) {
+				return nil, fmt.Errorf("wordgraph: line %d: invalid letter %q", line, f)
+			}
+			letters[i] = l
+		}
+		cost, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			return nil, fmt.Errorf("wordgraph: line %d: %w", line, err)
+		}
+		if !(cost >= 0) {
+			return nil, fmt.Errorf("wordgraph: line %d: invalid cost %v", line, cost)
+		}
+		if letters[0] == Gap && letters[1] == Gap {
+			return nil, fmt.Errorf("wordgraph: line %d: gap substituted for gap", line)
+		}
+		c.Set(letters[0], letters[1], cost)
+	}
+	return c, sc.Err()
+}
+
+// KeyboardCosts returns a Costs table where substitutions between letters
+// that are close on a QWERTY keyboard are cheap. The cost of a substitution
+// is half the distance between the keys, in key widths, up to a maximum of
+// one, so substituting adjacent keys costs one half.
+func KeyboardCosts() *Costs {
+	type position struct{ x, y float64 }
+	keys := make(map[rune]position)
+	for y, row := range []struct {
+		keys   string
+		offset float64
+	}{
+		{keys: "qwertyuiop", offset: 0},
+		{keys: "asdfghjkl", offset: 0.25},
+		{keys: "zxcvbnm", offset: 0.75},
+	} {
+		for x, k := range row.keys {
+			keys[k] = position{x: float64(x) + row.offset, y: float64(y)}
+		}
+	}
+
+	c := NewCosts()
+	for a, pa := range keys {
+		for b, pb := range keys {
+			if b <= a {
+				continue
+			}
+			d := math.Hypot(pa.x-pb.x, pa.y-pb.y)
+			if d < 2 {
+				c.Set(a, b, d/2)
+			}
+		}
+	}
+	return c
+}
+
+// VowelCosts returns a Costs table where substitutions between the vowels
+// a, e, i, o and u cost one half.
+func VowelCosts() *Costs {
+	const vowels = "aeiou"
+	c := NewCosts()
+	for _, a := range vowels {
+		for _, b := range vowels {
+			if a < b {
+				c.Set(a, b, 0.5)
+			}
+		}
+	}
+	return c
+}
+
+// LoadCosts returns the named preset Costs table, "keyboard" for
+// KeyboardCosts or "vowel" for VowelCosts, or otherwise the Costs table
+// read from the named file.
+func LoadCosts(name string) (*Costs, error) {
+	switch name {
+	case "keyboard":
+		return KeyboardCosts(), nil
+	case "vowel":
+		return VowelCosts(), nil
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadCosts(f)
+}
@@ -0,0 +1,234 @@
+package wordgraph
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/path"
+)
+
+func TestReadCosts(t *testing.T) {
+	const table = `# Vowels are cheap to substitute.
+a e 0.5
+	E  I	0.25
+
+# Plurals are cheap to form.
+- s 0.5
+é e 0
+`
+	c, err := ReadCosts(strings.NewReader(table))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, test := range []struct {
+		a, b rune
+		want float64
+	}{
+		{a: 'a', b: 'e', want: 0.5},
+		{a: 'e', b: 'a', want: 0.5},
+		{a: 'A', b: 'E', want: 0.5},
+		{a: 'i', b: 'e', want: 0.25},
+		{a: 's', b: Gap, want: 0.5},
+		{a: Gap, b: 's', want: 0.5},
+		{a: 'é', b: 'e', want: 0},
+		{a: 'a', b: 'i', want: 1},
+		{a: Gap, b: 't', want: 1},
+		{a: 'x', b: 'x', want: 0},
+	} {
+		if got := c.Cost(test.a, test.b); got != test.want {
+			t.Errorf("unexpected cost of %q for %q: got:%v want:%v", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+var readCostsErrorTests = []struct {
+	name  string
+	table string
+	want  string
+}{
+	{name: "too few fields", table: "a e\n", want: "line 1: expected two letters and a cost"},
+	{name: "too many fields", table: "# comment\na e 0.5 1\n", want: "line 2: expected two letters and a cost"},
+	{name: "multiple letters", table: "ae e 0.5\n", want: `line 1: invalid letter "ae"`},
+	{name: "numeral", table: "a 1 0.5\n", want: `line 1: invalid letter "1"`},
+	{name: "invalid cost", table: "a e half\n", want: `line 1: strconv.ParseFloat: parsing "half": invalid syntax`},
+	{name: "negative cost", table: "a e -1\n", want: "line 1: invalid cost -1"},
+	{name: "NaN cost", table: "a e NaN\n", want: "line 1: invalid cost NaN"},
+	{name: "gap for gap", table: "\n- - 1\n", want: "line 2: gap substituted for gap"},
+}
+
+func TestReadCostsErrors(t *testing.T) {
+	for _, test := range readCostsErrorTests {
+		_, err := ReadCosts(strings.NewReader(test.table))
+		if err == nil {
+			t.Errorf("expected error for %s", test.name)
+			continue
+		}
+		if want := "wordgraph: " + test.want; err.Error() != want {
+			t.Errorf("unexpected error for %s:\ngot: %v\nwant:%s", test.name, err, want)
+		}
+	}
+}
+
+func TestCostsBetween(t *testing.T) {
+	c := NewCosts()
+	c.Set('a', 'e', 0.5)
+	c.Set(Gap, 's', 0.25)
+	c.Set('é', Gap, 0.75)
+	for _, test := range []struct {
+		u, v string
+		want float64
+	}{
+		{u: "bat", v: "bet", want: 0.5},
+		{u: "bet", v: "bat", want: 0.5},
+		{u: "bat", v: "cat", want: 1},
+		{u: "cat", v: "cats", want: 0.25},
+		{u: "cats", v: "cat", want: 0.25},
+		{u: "cast", v: "cat", want: 0.25},
+		{u: "cat", v: "coat", want: 1},
+		{u: "café", v: "caf", want: 0.75},
+		{u: "caf", v: "café", want: 0.75},
+	} {
+		if got := c.between(test.u, test.v); got != test.want {
+			t.Errorf("unexpected cost between %q and %q: got:%v want:%v", test.u, test.v, got, test.want)
+		}
+	}
+}
+
+func TestCostsMin(t *testing.T) {
+	c := NewCosts()
+	if got := c.min(); got != 1 {
+		t.Errorf("unexpected minimum cost of empty table: got:%v want:1", got)
+	}
+	c.Set('a', 'e', 2)
+	if got := c.min(); got != 1 {
+		t.Errorf("unexpected minimum cost with expensive edit: got:%v want:1", got)
+	}
+	c.Set(Gap, 's', 0.25)
+	c.Set('i', 'o', 0.5)
+	if got := c.min(); got != 0.25 {
+		t.Errorf("unexpected minimum cost: got:%v want:0.25", got)
+	}
+}
+
+func TestPresetCosts(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		costs *Costs
+		a, b  rune
+		want  float64
+	}{
+		{name: "keyboard", costs: KeyboardCosts(), a: 'q', b: 'w', want: 0.5},
+		{name: "keyboard", costs: KeyboardCosts(), a: 'g', b: 'h', want: 0.5},
+		{name: "keyboard", costs: KeyboardCosts(), a: 'q', b: 'a', want: math.Hypot(0.25, 1) / 2},
+		{name: "keyboard", costs: KeyboardCosts(), a: 'q', b: 'e', want: 1},
+		{name: "keyboard", costs: KeyboardCosts(), a: 'q', b: 'p', want: 1},
+		{name: "keyboard", costs: KeyboardCosts(), a: 'z', b: 'x', want: 0.5},
+		{name: "keyboard", costs: KeyboardCosts(), a: Gap, b: 's', want: 1},
+		{name: "vowel", costs: VowelCosts(), a: 'a', b: 'e', want: 0.5},
+		{name: "vowel", costs: VowelCosts(), a: 'u', b: 'o', want: 0.5},
+		{name: "vowel", costs: VowelCosts(), a: 'a', b: 'b', want: 1},
+		{name: "vowel", costs: VowelCosts(), a: 'y', b: 'e', want: 1},
+		{name: "vowel", costs: VowelCosts(), a: 'i', b: 'i', want: 0},
+	} {
+		if got := test.costs.Cost(test.a, test.b); math.Abs(got-test.want) > 1e-12 {
+			t.Errorf("unexpected %s cost of %q for %q: got:%v want:%v", test.name, test.a, test.b, got, test.want)
+		}
+	}
+	if got := KeyboardCosts().min(); got != 0.5 {
+		t.Errorf("unexpected minimum keyboard cost: got:%v want:0.5", got)
+	}
+	if got := VowelCosts().min(); got != 0.5 {
+		t.Errorf("unexpected minimum vowel cost: got:%v want:0.5", got)
+	}
+}
+
+func TestLoadCosts(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "costs.txt")
+	err := os.WriteFile(name, []byte("a e 0.25\n"), 0o664)
+	if err != nil {
+		t.Fatalf("failed to write costs: %v", err)
+	}
+	for _, test := range []struct {
+		name string
+		a, b rune
+		want float64
+	}{
+		{name: "keyboard", a: 'q', b: 'w', want: 0.5},
+		{name: "vowel", a: 'a', b: 'e', want: 0.5},
+		{name: name, a: 'a', b: 'e', want: 0.25},
+		{name: name, a: 'a', b: 'i', want: 1},
+	} {
+		c, err := LoadCosts(test.name)
+		if err != nil {
+			t.Errorf("unexpected error loading %s costs: %v", test.name, err)
+			continue
+		}
+		if got := c.Cost(test.a, test.b); got != test.want {
+			t.Errorf("unexpected %s cost of %q for %q: got:%v want:%v", test.name, test.a, test.b, got, test.want)
+		}
+	}
+	_, err = LoadCosts(filepath.Join(t.TempDir(), "missing.txt"))
+	if err == nil {
+		t.Error("expected error for missing costs file")
+	}
+}
+
+func TestCostsHeuristic(t *testing.T) {
+	free := NewCosts()
+	free.Set('a', 'o', 0)
+	plural := NewCosts()
+	plural.Set(Gap, 's', 0.25)
+	for _, test := range []struct {
+		costs *Costs
+		edit  bool
+		u, v  string
+		want  float64
+	}{
+		{costs: VowelCosts(), u: "cat", v: "dog", want: 1.5},
+		{costs: VowelCosts(), u: "cat", v: "cot", want: 0.5},
+		{costs: free, u: "cat", v: "dog", want: 0},
+		{costs: plural, edit: true, u: "cat", v: "cats", want: 0.25},
+		{costs: plural, edit: true, u: "cat", v: "dogs", want: 1},
+	} {
+		for _, kind := range []Kind{Eager, Lazy} {
+			n := 3
+			opts := []Option{EditCosts(test.costs)}
+			if test.edit {
+				n = 0
+				opts = append(opts, Levenshtein())
+			}
+			g := New(kind, n, opts...)
+			g.Include(test.u)
+			g.Include(test.v)
+			h := g.(path.HeuristicCoster).HeuristicCost(g.NodeFor(test.u), g.NodeFor(test.v))
+			if h != test.want {
+				t.Errorf("unexpected heuristic cost from %q to %q: got:%v want:%v", test.u, test.v, h, test.want)
+			}
+		}
+	}
+}
+
+func TestVowelLadder(t *testing.T) {
+	// There are two three step ladders from bat to
+	// bid. The ladder through but and bud has two
+	// vowel substitutions, and the ladder through
+	// bag and big has only one.
+	words := []string{"bat", "but", "bud", "bid", "bag", "big"}
+	for _, kind := range []Kind{Eager, Lazy} {
+		g := New(kind, 3, EditCosts(VowelCosts()))
+		for _, w := range words {
+			g.Include(w)
+		}
+		ladder, weight := path.DijkstraFrom(g.NodeFor("bat"), g).To(g.NodeFor("bid").ID())
+		if got, want := ladderWords([][]graph.Node{ladder})[0], "bat but bud bid"; got != want {
+			t.Errorf("unexpected ladder: got:%q want:%q", got, want)
+		}
+		if weight != 2 {
+			t.Errorf("unexpected ladder weight: got:%v want:2", weight)
+		}
+	}
+}
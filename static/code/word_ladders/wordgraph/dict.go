@@ -0,0 +1,229 @@
+package wordgraph
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+)
+
+// Dictionaries is a list of dictionary file names. It implements the
+// flag.Value interface so that it can be given as a repeated flag.
+type Dictionaries []string
+
+// String implements the flag.Value String method.
+func (d *Dictionaries) String() string { return strings.Join(*d, ",") }
+
+// Set implements the flag.Value Set method.
+func (d *Dictionaries) Set(name string) error {
+	*d = append(*d, name)
+	return nil
+}
+
+// Status is the result of including a word read from a dictionary.
+type Status int
+
+const (
+	// Accepted is the status of words that are
+	// added to a graph.
+	Accepted Status = iota
+
+	// Rejected is the status of words that are
+	// not valid words or have the wrong length.
+	Rejected
+
+	// Duplicate is the status of words that have
+	// already been read.
+	Duplicate
+
+	// Filtered is the status of words that are
+	// removed by a Filter.
+	Filtered
+)
+
+// Counts holds the number of words read from a dictionary file with
+// each status.
+type Counts struct {
+	Name      string
+	Accepted  int
+	Rejected  int
+	Duplicate int
+	Filtered  int
+}
+
+// OpenDictionary opens the named dictionary file for reading. Files with
+// a .gz or .bz2 extension are decompressed. The name "-" opens the standard
+// input.
+func OpenDictionary(name string) (io.ReadCloser, error) {
+	if name == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	switch filepath.Ext(name) {
+	case ".gz":
+		r, err := gzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		return readCloser{Reader: r, closers: []io.Closer{r, f}}, nil
+	case ".bz2":
+		return readCloser{Reader: bzip2.NewReader(f), closers: []io.Closer{f}}, nil
+	default:
+		return f, nil
+	}
+}
+
+// readCloser is a decompressing reader of a file.
+type readCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+// Close closes the decompressor and the file.
+func (r readCloser) Close() error {
+	var err error
+	for _, c := range r.closers {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// ReadDictionaries reads the named dictionary files, calling include with
+// each line of the files in turn, and returns the counts of the statuses
+// returned by include for each file. Files with a .dic extension are read
+// as Hunspell dictionaries with the .aff file of the same name, and include
+// is called with each word generated by ReadHunspell.
+func ReadDictionaries(names []string, include func(word string) Status) ([]Counts, error) {
+	counts := make([]Counts, len(names))
+	for i, name := range names {
+		counts[i].Name = name
+		err := readDictionary(name, include, &counts[i])
+		if err != nil {
+			return counts[:i+1], err
+		}
+	}
+	return counts, nil
+}
+
+func readDictionary(name string, include func(word string) Status, counts *Counts) error {
+	if filepath.Ext(name) == ".dic" {
+		return readHunspell(name, include, counts)
+	}
+	r, err := OpenDictionary(name)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		counts.add(include(sc.Text()))
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// readHunspell reads the named Hunspell dictionary with the affix file
+// of the same name, calling include with each word it generates.
+func readHunspell(name string, include func(word string) Status, counts *Counts) error {
+	dic, err := OpenDictionary(name)
+	if err != nil {
+		return err
+	}
+	defer dic.Close()
+	aff, err := OpenDictionary(strings.TrimSuffix(name, ".dic") + ".aff")
+	if err != nil {
+		return err
+	}
+	defer aff.Close()
+	words, err := ReadHunspell(dic, aff)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	for _, w := range words {
+		counts.add(include(w))
+	}
+	return nil
+}
+
+// add counts a word with the given status.
+func (c *Counts) add(s Status) {
+	switch s {
+	case Accepted:
+		c.Accepted++
+	case Rejected:
+		c.Rejected++
+	case Duplicate:
+		c.Duplicate++
+	case Filtered:
+		c.Filtered++
+	}
+}
+
+// Includer returns a function for ReadDictionaries that includes words in g.
+func Includer(g Graph) func(word string) Status {
+	seen := make(map[string]bool)
+	return func(word string) Status {
+		word = strings.ToLower(word)
+		if seen[word] {
+			return Duplicate
+		}
+		g.Include(word)
+		if g.NodeFor(word) == nil {
+			return Rejected
+		}
+		seen[word] = true
+		return Accepted
+	}
+}
+
+// appender returns a function for ReadDictionaries that appends each
+// unique word to buf as a line in lower case.
+func appender(buf *bytes.Buffer) func(word string) Status {
+	seen := make(map[string]bool)
+	return func(word string) Status {
+		word = strings.ToLower(word)
+		switch {
+		case !IsWord(word):
+			return Rejected
+		case seen[word]:
+			return Duplicate
+		}
+		seen[word] = true
+		buf.WriteString(word)
+		buf.WriteByte('\n')
+		return Accepted
+	}
+}
+
+// WriteCounts writes a table of the counts of words read from each
+// dictionary file, and their totals, to w.
+func WriteCounts(w io.Writer, counts []Counts) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "accepted\trejected\tduplicate\tfiltered\t  file")
+	var total Counts
+	for _, c := range counts {
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%d\t  %s\n", c.Accepted, c.Rejected, c.Duplicate, c.Filtered, c.Name)
+		total.Accepted += c.Accepted
+		total.Rejected += c.Rejected
+		total.Duplicate += c.Duplicate
+		total.Filtered += c.Filtered
+	}
+	if len(counts) > 1 {
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%d\t  total\n", total.Accepted, total.Rejected, total.Duplicate, total.Filtered)
+	}
+	return tw.Flush()
+}
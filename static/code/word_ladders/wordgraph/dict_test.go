@@ -0,0 +1,112 @@
+package wordgraph
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// writeDictionaries writes plain and gzip compressed dictionary files to
+// dir and returns their names followed by the name of a bzip2 compressed
+// dictionary held in testdata. The bzip2 dictionary holds warm, worm,
+// word, Word, words and cord.
+func writeDictionaries(t *testing.T, dir string) []string {
+	plain := filepath.Join(dir, "words.txt")
+	err := os.WriteFile(plain, []byte("cold\nCold\ncord\ncard\nit's\n"), 0o664)
+	if err != nil {
+		t.Fatalf("failed to write dictionary: %v", err)
+	}
+
+	compressed := filepath.Join(dir, "words.txt.gz")
+	f, err := os.Create(compressed)
+	if err != nil {
+		t.Fatalf("failed to create dictionary: %v", err)
+	}
+	w := gzip.NewWriter(f)
+	_, err = w.Write([]byte("card\nward\nwarm\nwarmth\n"))
+	if err == nil {
+		err = w.Close()
+	}
+	if err == nil {
+		err = f.Close()
+	}
+	if err != nil {
+		t.Fatalf("failed to write dictionary: %v", err)
+	}
+
+	return []string{plain, compressed, filepath.Join("testdata", "words.txt.bz2")}
+}
+
+func TestReadDictionaries(t *testing.T) {
+	names := writeDictionaries(t, t.TempDir())
+	g := New(Lazy, 4)
+	counts, err := ReadDictionaries(names, Includer(g))
+	if err != nil {
+		t.Fatalf("failed to read dictionaries: %v", err)
+	}
+
+	wantCounts := []Counts{
+		{Name: names[0], Accepted: 3, Rejected: 1, Duplicate: 1},
+		{Name: names[1], Accepted: 2, Rejected: 1, Duplicate: 1},
+		{Name: names[2], Accepted: 2, Rejected: 1, Duplicate: 3},
+	}
+	if !reflect.DeepEqual(counts, wantCounts) {
+		t.Errorf("unexpected counts:\ngot: %+v\nwant:%+v", counts, wantCounts)
+	}
+	wantWords := []string{"card", "cold", "cord", "ward", "warm", "word", "worm"}
+	if got := nodeWords(graph.NodesOf(g.Nodes())); !reflect.DeepEqual(got, wantWords) {
+		t.Errorf("unexpected words:\ngot: %v\nwant:%v", got, wantWords)
+	}
+
+	_, err = ReadDictionaries([]string{filepath.Join("testdata", "missing.txt")}, Includer(g))
+	if !os.IsNotExist(err) {
+		t.Errorf("unexpected error for missing dictionary: %v", err)
+	}
+}
+
+func TestAppender(t *testing.T) {
+	names := writeDictionaries(t, t.TempDir())
+	var buf bytes.Buffer
+	counts, err := ReadDictionaries(names, appender(&buf))
+	if err != nil {
+		t.Fatalf("failed to read dictionaries: %v", err)
+	}
+
+	wantCounts := []Counts{
+		{Name: names[0], Accepted: 3, Rejected: 1, Duplicate: 1},
+		{Name: names[1], Accepted: 3, Rejected: 0, Duplicate: 1},
+		{Name: names[2], Accepted: 3, Rejected: 0, Duplicate: 3},
+	}
+	if !reflect.DeepEqual(counts, wantCounts) {
+		t.Errorf("unexpected counts:\ngot: %+v\nwant:%+v", counts, wantCounts)
+	}
+	want := "cold\ncord\ncard\nward\nwarm\nwarmth\nworm\nword\nwords\n"
+	if got := buf.String(); got != want {
+		t.Errorf("unexpected union:\ngot: %q\nwant:%q", got, want)
+	}
+}
+
+func TestWriteCounts(t *testing.T) {
+	var buf strings.Builder
+	err := WriteCounts(&buf, []Counts{
+		{Name: "words.txt", Accepted: 3, Rejected: 1, Duplicate: 1},
+		{Name: "words.txt.gz", Accepted: 120, Rejected: 0, Duplicate: 4, Filtered: 2},
+	})
+	if err != nil {
+		t.Fatalf("failed to write counts: %v", err)
+	}
+	want := `  accepted  rejected  duplicate  filtered  file
+         3         1          1         0  words.txt
+       120         0          4         2  words.txt.gz
+       123         1          5         2  total
+`
+	if got := buf.String(); got != want {
+		t.Errorf("unexpected counts table:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
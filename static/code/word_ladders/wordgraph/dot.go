@@ -0,0 +1,97 @@
+package wordgraph
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/encoding/dot"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// MarshalDOT returns the DOT encoding of the word graph g with the given
+// name. The words and steps of the ladders are highlighted, so that an
+// answer can be rendered with Graphviz. If induced is true, only the
+// subgraph induced by the words of the ladders is encoded.
+func MarshalDOT(g Graph, name string, ladders [][]graph.Node, induced bool) ([]byte, error) {
+	words := make(map[int64]bool)
+	steps := make(map[[2]int64]bool)
+	for _, l := range ladders {
+		for i, n := range l {
+			words[n.ID()] = true
+			if i != 0 {
+				steps[pairOf(l[i-1].ID(), n.ID())] = true
+			}
+		}
+	}
+
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID() < nodes[j].ID() })
+	dg := dotGraph{simple.NewUndirectedGraph()}
+	for _, n := range nodes {
+		if induced && !words[n.ID()] {
+			continue
+		}
+		u := n.(node)
+		u.ladder = words[u.id]
+		dg.AddNode(u)
+	}
+	for _, n := range nodes {
+		u := dg.Node(n.ID())
+		if u == nil {
+			continue
+		}
+		for to := g.From(n.ID()); to.Next(); {
+			v := dg.Node(to.Node().ID())
+			if v == nil || v.ID() < u.ID() {
+				continue
+			}
+			dg.SetEdge(edge{f: u.(node), t: v.(node), ladder: steps[pairOf(u.ID(), v.ID())]})
+		}
+	}
+	return dot.Marshal(dg, name, "", "\t")
+}
+
+// pairOf returns the IDs of the ends of an undirected edge in order.
+func pairOf(uid, vid int64) [2]int64 {
+	if vid < uid {
+		uid, vid = vid, uid
+	}
+	return [2]int64{uid, vid}
+}
+
+// dotGraph is a word graph prepared for DOT encoding.
+type dotGraph struct {
+	*simple.UndirectedGraph
+}
+
+// DOTAttributers implements the dot.Attributers interface.
+func (dotGraph) DOTAttributers() (graph, node, edge encoding.Attributer) {
+	return attributes{{Key: "rankdir", Value: "LR"}},
+		attributes{{Key: "shape", Value: "box"}, {Key: "style", Value: "rounded"}},
+		attributes(nil)
+}
+
+// attributes is a set of DOT attributes.
+type attributes []encoding.Attribute
+
+func (a attributes) Attributes() []encoding.Attribute { return a }
+
+// DOTID implements the dot.Node interface, identifying nodes by their word.
+func (n node) DOTID() string { return n.word }
+
+// Attributes implements the encoding.Attributer interface.
+func (n node) Attributes() []encoding.Attribute {
+	if !n.ladder {
+		return nil
+	}
+	return []encoding.Attribute{{Key: "color", Value: "red"}, {Key: "fontcolor", Value: "red"}}
+}
+
+// Attributes implements the encoding.Attributer interface.
+func (e edge) Attributes() []encoding.Attribute {
+	if !e.ladder {
+		return nil
+	}
+	return []encoding.Attribute{{Key: "color", Value: "red"}, {Key: "penwidth", Value: "2"}}
+}
@@ -0,0 +1,128 @@
+package wordgraph
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+var marshalDOTTests = []struct {
+	induced bool
+	want    string
+}{
+	{
+		induced: false,
+		want: `strict graph ladder {
+	graph [
+		rankdir=LR
+	];
+	node [
+		shape=box
+		style=rounded
+	];
+
+	// Node definitions.
+	cats [
+		color=red
+		fontcolor=red
+	];
+	cots [
+		color=red
+		fontcolor=red
+	];
+	cogs;
+	dogs [
+		color=red
+		fontcolor=red
+	];
+	dots [
+		color=red
+		fontcolor=red
+	];
+
+	// Edge definitions.
+	cats -- cots [
+		color=red
+		penwidth=2
+	];
+	cots -- cogs;
+	cots -- dots [
+		color=red
+		penwidth=2
+	];
+	cogs -- dogs;
+	dogs -- dots [
+		color=red
+		penwidth=2
+	];
+}`,
+	},
+	{
+		induced: true,
+		want: `strict graph ladder {
+	graph [
+		rankdir=LR
+	];
+	node [
+		shape=box
+		style=rounded
+	];
+
+	// Node definitions.
+	cats [
+		color=red
+		fontcolor=red
+	];
+	cots [
+		color=red
+		fontcolor=red
+	];
+	dogs [
+		color=red
+		fontcolor=red
+	];
+	dots [
+		color=red
+		fontcolor=red
+	];
+
+	// Edge definitions.
+	cats -- cots [
+		color=red
+		penwidth=2
+	];
+	cots -- dots [
+		color=red
+		penwidth=2
+	];
+	dogs -- dots [
+		color=red
+		penwidth=2
+	];
+}`,
+	},
+}
+
+func TestMarshalDOT(t *testing.T) {
+	for _, test := range conformanceTests {
+		g := New(test.kind, test.n, test.opts...)
+		for _, w := range []string{"cats", "cots", "cogs", "dogs", "dots"} {
+			g.Include(w)
+		}
+		var ladder []graph.Node
+		for _, w := range []string{"cats", "cots", "dots", "dogs"} {
+			ladder = append(ladder, g.NodeFor(w))
+		}
+
+		for _, enc := range marshalDOTTests {
+			got, err := MarshalDOT(g, "ladder", [][]graph.Node{ladder}, enc.induced)
+			if err != nil {
+				t.Errorf("unexpected error for %s graph induced=%t: %v", test.name, enc.induced, err)
+				continue
+			}
+			if string(got) != enc.want {
+				t.Errorf("unexpected DOT encoding for %s graph induced=%t:\ngot:\n%s\nwant:\n%s", test.name, enc.induced, got, enc.want)
+			}
+		}
+	}
+}
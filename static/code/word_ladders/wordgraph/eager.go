@@ -0,0 +1,108 @@
+package wordgraph
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// EagerGraph is a graph of distance-1 word paths. It encapsulates
+// a Gonum simple.UndirectedGraph to provide a domain-specific API for
+// handling word ladder searches.
+type EagerGraph struct {
+	n     int
+	edit  bool
+	alpha alphabet
+	index
+	weights
+
+	*simple.UndirectedGraph
+}
+
+// newEagerGraph returns a new EagerGraph for words of n letters.
+func newEagerGraph(n int, cfg config) *EagerGraph {
+	return &EagerGraph{
+		n:               n,
+		edit:            cfg.edit,
+		alpha:           newAlphabet(cfg.letters),
+		index:           newIndex(),
+		weights:         newWeights(cfg),
+		UndirectedGraph: simple.NewUndirectedGraph(),
+	}
+}
+
+// Include adds word to the graph and connects it to its distance-1
+// neighbours.
+func (g *EagerGraph) Include(word string) {
+	word = strings.ToLower(word)
+	if (g.n != 0 && utf8.RuneCountInString(word) != g.n) || !IsWord(word) {
+		return
+	}
+	if _, exists := g.ids[word]; exists {
+		return
+	}
+	if !g.alpha.admit(word) {
+		return
+	}
+
+	// We know the node is not yet in the graph, so we can add it.
+	uid := g.add(word)
+	u := node{word: word, id: uid}
+	g.UndirectedGraph.AddNode(u)
+
+	// Join to all the neighbours from words we already know.
+	for it := newNeighbours(g.index, uid, g.edit); it.Next(); {
+		v := g.UndirectedGraph.Node(it.Node().ID())
+		g.SetEdge(simple.Edge{F: u, T: v})
+	}
+}
+
+// NodeFor returns a graph.Node representing the word for inclusion in an
+// EagerGraph.
+func (g EagerGraph) NodeFor(word string) graph.Node {
+	id, ok := g.ids[word]
+	if !ok {
+		return nil
+	}
+	return g.UndirectedGraph.Node(id)
+}
+
+// HeuristicCost implements the path.HeuristicCoster interface. It returns
+// the Hamming distance between the words of x and y, or for Levenshtein
+// graphs, the Levenshtein distance, scaled by the minimum edit cost. Both
+// are admissible heuristics since each step of a ladder has a weight of
+// at least the minimum edit cost.
+func (g EagerGraph) HeuristicCost(x, y graph.Node) float64 {
+	return g.weights.heuristic(g.heuristicCost(x, y, g.edit))
+}
+
+// Weight implements the graph.Weighted Weight method. Edges have unit
+// weight unless the graph was constructed with the Familiarity or
+// EditCosts options.
+func (g EagerGraph) Weight(xid, yid int64) (w float64, ok bool) {
+	if xid == yid {
+		return 0, true
+	}
+	if !g.HasEdgeBetween(xid, yid) {
+		return math.Inf(1), false
+	}
+	return g.weights.between(g.words[xid], g.words[yid]), true
+}
+
+// WeightedEdge implements the graph.Weighted WeightedEdge method.
+func (g EagerGraph) WeightedEdge(uid, vid int64) graph.WeightedEdge {
+	w, ok := g.Weight(uid, vid)
+	if !ok || uid == vid {
+		return nil
+	}
+	return weightedEdge{edge: edge{f: node{word: g.words[uid], id: uid}, t: node{word: g.words[vid], id: vid}}, w: w}
+}
+
+// WeightedEdgeBetween implements the graph.WeightedUndirected
+// WeightedEdgeBetween method.
+func (g EagerGraph) WeightedEdgeBetween(xid, yid int64) graph.WeightedEdge {
+	return g.WeightedEdge(xid, yid)
+}
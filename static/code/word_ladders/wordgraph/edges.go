@@ -0,0 +1,204 @@
+package wordgraph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding/dot"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// EdgeGraph is a graph of labelled nodes joined by edges that are read
+// from an edge list or a DOT file rather than implied by word distance.
+// It allows ladder queries to be run over graphs that are not made of
+// words. Edges have unit weight.
+type EdgeGraph struct {
+	ids map[string]int64
+	*simple.UndirectedGraph
+}
+
+// NewEdgeGraph returns a new empty EdgeGraph.
+func NewEdgeGraph() *EdgeGraph {
+	return &EdgeGraph{
+		ids:             make(map[string]int64),
+		UndirectedGraph: simple.NewUndirectedGraph(),
+	}
+}
+
+// Include adds a node with the given label to the graph if it does not
+// already hold one.
+func (g *EdgeGraph) Include(label string) {
+	if _, exists := g.ids[label]; exists {
+		return
+	}
+	id := int64(len(g.ids))
+	g.ids[label] = id
+	g.UndirectedGraph.AddNode(node{word: label, id: id})
+}
+
+// Join adds an edge between the nodes with labels u and v, including the
+// nodes in the graph if necessary. It panics if u and v are the same.
+func (g *EdgeGraph) Join(u, v string) {
+	g.Include(u)
+	g.Include(v)
+	g.SetEdge(simple.Edge{F: g.NodeFor(u), T: g.NodeFor(v)})
+}
+
+// NodeFor returns a graph.Node representing the label, or nil if the
+// label is not in the graph.
+func (g *EdgeGraph) NodeFor(label string) graph.Node {
+	id, ok := g.ids[label]
+	if !ok {
+		return nil
+	}
+	return g.UndirectedGraph.Node(id)
+}
+
+// Weight implements the graph.Weighted Weight method.
+func (g *EdgeGraph) Weight(xid, yid int64) (w float64, ok bool) {
+	if xid == yid {
+		return 0, true
+	}
+	if !g.HasEdgeBetween(xid, yid) {
+		return math.Inf(1), false
+	}
+	return 1, true
+}
+
+// WeightedEdge implements the graph.Weighted WeightedEdge method.
+func (g *EdgeGraph) WeightedEdge(uid, vid int64) graph.WeightedEdge {
+	if uid == vid || !g.HasEdgeBetween(uid, vid) {
+		return nil
+	}
+	u := g.UndirectedGraph.Node(uid).(node)
+	v := g.UndirectedGraph.Node(vid).(node)
+	return weightedEdge{edge: edge{f: u, t: v}, w: 1}
+}
+
+// WeightedEdgeBetween implements the graph.WeightedUndirected
+// WeightedEdgeBetween method.
+func (g *EdgeGraph) WeightedEdgeBetween(xid, yid int64) graph.WeightedEdge {
+	return g.WeightedEdge(xid, yid)
+}
+
+// ReadEdgeList returns a graph read from an edge list. Each line of the
+// list holds the labels of two nodes joined by an edge, or the label of a
+// single node, separated by white space. Blank lines and lines starting
+// with # are ignored.
+func ReadEdgeList(r io.Reader) (*EdgeGraph, error) {
+	g := NewEdgeGraph()
+	sc := bufio.NewScanner(r)
+	var line int
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		f := strings.Fields(text)
+		switch len(f) {
+		case 1:
+			g.Include(f[0])
+		case 2:
+			if f[0] == f[1] {
+				return nil, fmt.Errorf("wordgraph: line %d: self loop on %q", line, f[0])
+			}
+			g.Join(f[0], f[1])
+		default:
+			return nil, fmt.Errorf("wordgraph: line %d: expected one or two labels: %q", line, text)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// ReadDOT returns a graph read from a DOT encoding. Nodes are labelled
+// by their DOT IDs. Edge directions and attributes are ignored, and self
+// loops are an error.
+func ReadDOT(r io.Reader) (*EdgeGraph, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	dst := &dotBuilder{UndirectedGraph: simple.NewUndirectedGraph()}
+	err = dot.Unmarshal(b, dst)
+	if err != nil {
+		return nil, err
+	}
+	if dst.loop != nil {
+		return nil, fmt.Errorf("wordgraph: self loop on %q", dst.loop.label)
+	}
+
+	// Include the nodes in the order they were
+	// read so that their IDs are deterministic.
+	nodes := graph.NodesOf(dst.Nodes())
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID() < nodes[j].ID() })
+	g := NewEdgeGraph()
+	for _, n := range nodes {
+		g.Include(n.(*dotNode).label)
+	}
+	for _, e := range graph.EdgesOf(dst.Edges()) {
+		g.Join(e.From().(*dotNode).label, e.To().(*dotNode).label)
+	}
+	return g, nil
+}
+
+// ReadGraphFile returns the graph held in the named file. Files with a
+// .dot or .gv extension are read as DOT, and others as edge lists.
+func ReadGraphFile(name string) (*EdgeGraph, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	switch filepath.Ext(name) {
+	case ".dot", ".gv":
+		return ReadDOT(f)
+	default:
+		return ReadEdgeList(f)
+	}
+}
+
+// dotBuilder is a graph that DOT encodings are unmarshaled into. Self
+// loops are not added to the graph, and the node of the first is held
+// in loop.
+type dotBuilder struct {
+	*simple.UndirectedGraph
+	loop *dotNode
+}
+
+// NewNode implements the graph.NodeAdder NewNode method.
+func (g *dotBuilder) NewNode() graph.Node {
+	return &dotNode{id: g.UndirectedGraph.NewNode().ID()}
+}
+
+// SetEdge implements the graph.EdgeAdder SetEdge method.
+func (g *dotBuilder) SetEdge(e graph.Edge) {
+	if e.From().ID() == e.To().ID() {
+		if g.loop == nil {
+			g.loop = e.From().(*dotNode)
+		}
+		return
+	}
+	g.UndirectedGraph.SetEdge(e)
+}
+
+// dotNode is a node read from a DOT encoding.
+type dotNode struct {
+	id    int64
+	label string
+}
+
+func (n *dotNode) ID() int64 { return n.id }
+
+// SetDOTID implements the dot.DOTIDSetter interface.
+func (n *dotNode) SetDOTID(id string) { n.label = id }
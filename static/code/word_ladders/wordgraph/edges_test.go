@@ -0,0 +1,227 @@
+package wordgraph
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// edgeWords returns the labels of the nodes of g and the
+// labels of the ends of its edges in a canonical order.
+func edgeWords(g *EdgeGraph) (nodes []string, edges []string) {
+	for _, n := range graph.NodesOf(g.Nodes()) {
+		nodes = append(nodes, n.(node).word)
+	}
+	sort.Strings(nodes)
+	for _, e := range graph.EdgesOf(g.Edges()) {
+		u, v := e.From().(node).word, e.To().(node).word
+		if v < u {
+			u, v = v, u
+		}
+		edges = append(edges, u+"-"+v)
+	}
+	sort.Strings(edges)
+	return nodes, edges
+}
+
+var readEdgeTests = []struct {
+	name   string
+	format string
+	src    string
+
+	wantNodes []string
+	wantEdges []string
+}{
+	{
+		name:   "edge list",
+		format: "edges",
+		src: `# A square with a tail and an isolated node.
+a b
+b	c
+  c d
+d a
+
+d e
+a b
+f
+`,
+		wantNodes: []string{"a", "b", "c", "d", "e", "f"},
+		wantEdges: []string{"a-b", "a-d", "b-c", "c-d", "d-e"},
+	},
+	{
+		name:   "undirected DOT",
+		format: "dot",
+		src: `graph square {
+	a -- b -- c -- d -- a
+	d -- e [color=red]
+	f
+}`,
+		wantNodes: []string{"a", "b", "c", "d", "e", "f"},
+		wantEdges: []string{"a-b", "a-d", "b-c", "c-d", "d-e"},
+	},
+	{
+		name:   "directed DOT",
+		format: "dot",
+		src: `digraph square {
+	a -> b -> c -> d -> a
+	b -> a
+	e -> d
+	"f g"
+}`,
+		wantNodes: []string{"a", "b", "c", "d", "e", "f g"},
+		wantEdges: []string{"a-b", "a-d", "b-c", "c-d", "d-e"},
+	},
+}
+
+func TestReadEdges(t *testing.T) {
+	for _, test := range readEdgeTests {
+		var (
+			g   *EdgeGraph
+			err error
+		)
+		switch test.format {
+		case "edges":
+			g, err = ReadEdgeList(strings.NewReader(test.src))
+		case "dot":
+			g, err = ReadDOT(strings.NewReader(test.src))
+		}
+		if err != nil {
+			t.Errorf("unexpected error reading %s: %v", test.name, err)
+			continue
+		}
+		nodes, edges := edgeWords(g)
+		if !reflect.DeepEqual(nodes, test.wantNodes) {
+			t.Errorf("unexpected nodes in %s:\ngot: %q\nwant:%q", test.name, nodes, test.wantNodes)
+		}
+		if !reflect.DeepEqual(edges, test.wantEdges) {
+			t.Errorf("unexpected edges in %s:\ngot: %q\nwant:%q", test.name, edges, test.wantEdges)
+		}
+	}
+}
+
+var readEdgeErrorTests = []struct {
+	name   string
+	format string
+	src    string
+	want   string
+}{
+	{name: "edge list self loop", format: "edges", src: "a b\n\na a\n", want: `wordgraph: line 3: self loop on "a"`},
+	{name: "edge list fields", format: "edges", src: "a b c\n", want: `wordgraph: line 1: expected one or two labels: "a b c"`},
+	{name: "DOT self loop", format: "dot", src: "graph { a -- b; b -- b }", want: `wordgraph: self loop on "b"`},
+	{name: "DOT chained self loop", format: "dot", src: "graph { a -- b -- b }", want: `wordgraph: self loop on "b"`},
+	{name: "DOT directed self loop", format: "dot", src: "digraph { c -> c }", want: `wordgraph: self loop on "c"`},
+	{name: "DOT syntax", format: "dot", src: "graph { a -- }"},
+}
+
+func TestReadEdgesErrors(t *testing.T) {
+	for _, test := range readEdgeErrorTests {
+		var err error
+		switch test.format {
+		case "edges":
+			_, err = ReadEdgeList(strings.NewReader(test.src))
+		case "dot":
+			_, err = ReadDOT(strings.NewReader(test.src))
+		}
+		if err == nil {
+			t.Errorf("expected error for %s", test.name)
+			continue
+		}
+		if test.want != "" && err.Error() != test.want {
+			t.Errorf("unexpected error for %s:\ngot: %v\nwant:%s", test.name, err, test.want)
+		}
+	}
+}
+
+func TestReadGraphFile(t *testing.T) {
+	dir := t.TempDir()
+	for _, test := range []struct {
+		name string
+		src  string
+	}{
+		{name: "square.txt", src: "a b\nb c\nc d\nd a\n"},
+		{name: "square.dot", src: "graph { a -- b -- c -- d -- a }"},
+		{name: "square.gv", src: "graph { a -- b -- c -- d -- a }"},
+	} {
+		path := filepath.Join(dir, test.name)
+		err := os.WriteFile(path, []byte(test.src), 0o664)
+		if err != nil {
+			t.Fatalf("failed to write graph file: %v", err)
+		}
+		g, err := ReadGraphFile(path)
+		if err != nil {
+			t.Errorf("unexpected error reading %s: %v", test.name, err)
+			continue
+		}
+		_, edges := edgeWords(g)
+		if want := []string{"a-b", "a-d", "b-c", "c-d"}; !reflect.DeepEqual(edges, want) {
+			t.Errorf("unexpected edges in %s:\ngot: %q\nwant:%q", test.name, edges, want)
+		}
+	}
+	_, err := ReadGraphFile(filepath.Join(dir, "missing.txt"))
+	if err == nil {
+		t.Error("expected error for missing graph file")
+	}
+}
+
+func TestEdgeGraph(t *testing.T) {
+	g := NewEdgeGraph()
+	g.Join("a", "b")
+	g.Join("b", "c")
+	g.Include("b")
+	g.Include("d")
+
+	if n := g.Nodes().Len(); n != 4 {
+		t.Errorf("unexpected number of nodes: got:%d want:4", n)
+	}
+	for i, label := range []string{"a", "b", "c", "d"} {
+		n := g.NodeFor(label)
+		if n == nil {
+			t.Errorf("missing node for %q", label)
+			continue
+		}
+		if n.ID() != int64(i) || n.(node).word != label {
+			t.Errorf("unexpected node for %q: got:%d %q want:%d %q", label, n.ID(), n.(node).word, i, label)
+		}
+	}
+	if n := g.NodeFor("e"); n != nil {
+		t.Errorf("unexpected node for missing label: %v", n)
+	}
+
+	a, b, c := g.NodeFor("a").ID(), g.NodeFor("b").ID(), g.NodeFor("c").ID()
+	for _, test := range []struct {
+		x, y int64
+		w    float64
+		ok   bool
+	}{
+		{x: a, y: a, w: 0, ok: true},
+		{x: a, y: b, w: 1, ok: true},
+		{x: b, y: a, w: 1, ok: true},
+		{x: a, y: c, w: math.Inf(1), ok: false},
+	} {
+		w, ok := g.Weight(test.x, test.y)
+		if w != test.w || ok != test.ok {
+			t.Errorf("unexpected weight between %d and %d: got:%v %t want:%v %t", test.x, test.y, w, ok, test.w, test.ok)
+		}
+	}
+
+	e := g.WeightedEdgeBetween(b, a)
+	if e == nil || e.From().ID() != b || e.To().ID() != a || e.Weight() != 1 {
+		t.Errorf("unexpected edge between b and a: %v", e)
+	}
+	for _, pair := range [][2]int64{{a, a}, {a, c}} {
+		if e := g.WeightedEdge(pair[0], pair[1]); e != nil {
+			t.Errorf("unexpected edge between %d and %d: %v", pair[0], pair[1], e)
+		}
+	}
+
+	ladder, _ := BidirectionalBetween(g, g.NodeFor("a"), g.NodeFor("c"))
+	if got := nodeWords(ladder); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Errorf("unexpected ladder: got:%q want:%q", got, []string{"a", "b", "c"})
+	}
+}
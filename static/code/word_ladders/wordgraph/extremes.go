@@ -0,0 +1,228 @@
+package wordgraph
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/path"
+)
+
+// Longest returns the number of steps in the longest of the shortest paths
+// between pairs of nodes in g, treating each edge as a single step, and the
+// IDs of the pairs of nodes joined by paths of that length. Pairs are ordered
+// by the IDs of their nodes, and the first node of each pair has the lower ID.
+//
+// Longest performs a breadth-first search from each node, so unlike
+// path.DijkstraAllPaths it only needs memory proportional to the number of
+// nodes in g. The searches are run by the given number of workers, which
+// must not be less than one, and g must be safe for concurrent queries.
+func Longest(g graph.Graph, workers int) (length int, ends [][2]int64) {
+	nodes, indexOf := sortedNodes(g)
+	best := bySource(len(nodes), workers, func() func(int, *extreme) {
+		b := newLayers(len(nodes))
+		return func(i int, best *extreme) {
+			b.walk(g, nodes, indexOf, i)
+			for j := i + 1; j < len(nodes); j++ {
+				if b.depth[j] > 0 {
+					best.update(b.depth[j], nodes[i].ID(), nodes[j].ID())
+				}
+			}
+		}
+	})
+	return best.value, best.ends
+}
+
+// Widest returns the number of shortest paths between the pairs of nodes in
+// g that are joined by the most shortest paths, treating each edge as a
+// single step, and the IDs of those pairs. Pairs are ordered as described
+// for Longest, and the searches are run in the same way.
+//
+// The shortest paths are counted during each breadth-first search rather
+// than enumerated, so the time taken does not grow with the number of
+// paths. Counts saturate at math.MaxInt rather than overflowing, so if
+// width is math.MaxInt, the pairs are joined by at least that many paths.
+func Widest(g graph.Graph, workers int) (width int, ends [][2]int64) {
+	nodes, indexOf := sortedNodes(g)
+	best := bySource(len(nodes), workers, func() func(int, *extreme) {
+		b := newLayers(len(nodes))
+		return func(i int, best *extreme) {
+			b.walk(g, nodes, indexOf, i)
+			for j := i + 1; j < len(nodes); j++ {
+				if b.paths[j] > 0 {
+					best.update(b.paths[j], nodes[i].ID(), nodes[j].ID())
+				}
+			}
+		}
+	})
+	return best.value, best.ends
+}
+
+// AllTo returns up to max of the shortest paths in pth to the node with
+// the given ID, in the order they are returned by pth.AllTo. If max is
+// zero, all the shortest paths are returned. The enumeration stops once
+// max paths have been found, so only the returned paths are constructed
+// however many shortest paths there are.
+func AllTo(pth path.ShortestAlts, vid int64, max int) (paths [][]graph.Node) {
+	if max == 0 {
+		paths, _ = pth.AllTo(vid)
+		return paths
+	}
+	defer func() {
+		r := recover()
+		if _, ok := r.(enough); r != nil && !ok {
+			panic(r)
+		}
+	}()
+	pth.AllToFunc(vid, func(p []graph.Node) {
+		paths = append(paths, append([]graph.Node(nil), p...))
+		if len(paths) == max {
+			// There is no way to stop AllToFunc
+			// other than unwinding the enumeration.
+			panic(enough{})
+		}
+	})
+	return paths
+}
+
+// enough is the panic value used by AllTo to stop enumerating paths.
+type enough struct{}
+
+// sortedNodes returns the nodes of g sorted by ID and a map from node
+// IDs to indexes into the returned slice.
+func sortedNodes(g graph.Graph) ([]graph.Node, map[int64]int) {
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID() < nodes[j].ID() })
+	indexOf := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+	return nodes, indexOf
+}
+
+// extreme is the running maximum of a measure of the paths between pairs
+// of nodes, and the IDs of the pairs of nodes attaining it.
+type extreme struct {
+	value int
+	ends  [][2]int64
+}
+
+// update includes the value of the measure for the pair of nodes u and v.
+func (e *extreme) update(value int, u, v int64) {
+	switch {
+	case value > e.value:
+		e.value = value
+		e.ends = [][2]int64{{u, v}}
+	case value == e.value:
+		e.ends = append(e.ends, [2]int64{u, v})
+	}
+}
+
+// merge includes the pairs of nodes held by o.
+func (e *extreme) merge(o extreme) {
+	switch {
+	case o.value > e.value:
+		e.value = o.value
+		e.ends = o.ends
+	case o.value == e.value:
+		e.ends = append(e.ends, o.ends...)
+	}
+}
+
+// bySource calls a search function for each of n source node indexes
+// using a pool of workers. Each worker obtains its own search function
+// from newSearch and holds its own running extreme. The extremes of the
+// workers are merged, with the pairs of nodes sorted by ID so that the
+// result does not depend on the number of workers or their scheduling.
+func bySource(n, workers int, newSearch func() func(i int, best *extreme)) extreme {
+	if workers < 1 {
+		panic("wordgraph: fewer than one worker")
+	}
+	sources := make(chan int)
+	results := make([]extreme, workers)
+	var wg sync.WaitGroup
+	for w := range results {
+		wg.Add(1)
+		go func(best *extreme) {
+			defer wg.Done()
+			search := newSearch()
+			for i := range sources {
+				search(i, best)
+			}
+		}(&results[w])
+	}
+	for i := 0; i < n; i++ {
+		sources <- i
+	}
+	close(sources)
+	wg.Wait()
+
+	var best extreme
+	for _, r := range results {
+		best.merge(r)
+	}
+	sort.Slice(best.ends, func(i, j int) bool {
+		a, b := best.ends[i], best.ends[j]
+		return a[0] < b[0] || (a[0] == b[0] && a[1] < b[1])
+	})
+	return best
+}
+
+// layers is a reusable breadth-first search over the nodes of a graph
+// that records the depth of each node from the root of the search and
+// the number of shortest paths from the root to each node.
+type layers struct {
+	depth []int
+	paths []int
+	queue []int
+}
+
+// newLayers returns a new search for a graph with n nodes.
+func newLayers(n int) *layers {
+	return &layers{
+		depth: make([]int, n),
+		paths: make([]int, n),
+		queue: make([]int, 0, n),
+	}
+}
+
+// walk performs a breadth-first search from the node at index root in
+// nodes, setting the depth of each node in the search, or -1 if the node
+// is not reachable from root, and the number of shortest paths to it.
+// Each node is reached by the shortest paths to its neighbours in the
+// previous layer, so their counts are summed as the layer is expanded,
+// saturating at math.MaxInt.
+func (b *layers) walk(g graph.Graph, nodes []graph.Node, indexOf map[int64]int, root int) {
+	for i := range b.depth {
+		b.depth[i] = -1
+		b.paths[i] = 0
+	}
+	b.depth[root] = 0
+	b.paths[root] = 1
+	b.queue = append(b.queue[:0], root)
+	for head := 0; head < len(b.queue); head++ {
+		u := b.queue[head]
+		to := g.From(nodes[u].ID())
+		for to.Next() {
+			v := indexOf[to.Node().ID()]
+			switch b.depth[v] {
+			case -1:
+				b.depth[v] = b.depth[u] + 1
+				b.paths[v] = b.paths[u]
+				b.queue = append(b.queue, v)
+			case b.depth[u] + 1:
+				b.paths[v] = saturatingAdd(b.paths[v], b.paths[u])
+			}
+		}
+	}
+}
+
+// saturatingAdd returns the sum of the non-negative integers a and b, or
+// math.MaxInt if the sum overflows.
+func saturatingAdd(a, b int) int {
+	if a > math.MaxInt-b {
+		return math.MaxInt
+	}
+	return a + b
+}
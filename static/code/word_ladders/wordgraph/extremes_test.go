@@ -0,0 +1,120 @@
+package wordgraph
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/path"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestExtremes(t *testing.T) {
+	for _, test := range conformanceTests {
+		g := New(test.kind, test.n, test.opts...)
+		for _, w := range testWords {
+			g.Include(w)
+		}
+
+		// Find the longest and widest ladders from
+		// the shortest paths between all pairs.
+		var longest, widest extreme
+		pths := path.DijkstraAllPaths(g)
+		nodes := graph.NodesOf(g.Nodes())
+		sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID() < nodes[j].ID() })
+		for i, u := range nodes {
+			for _, v := range nodes[i+1:] {
+				length := pths.Weight(u.ID(), v.ID())
+				if math.IsInf(length, 1) {
+					continue
+				}
+				longest.update(int(length), u.ID(), v.ID())
+				ladders, _ := pths.AllBetween(u.ID(), v.ID())
+				widest.update(len(ladders), u.ID(), v.ID())
+			}
+		}
+
+		for _, workers := range []int{1, 4} {
+			length, ends := Longest(g, workers)
+			if length != longest.value || !reflect.DeepEqual(ends, longest.ends) {
+				t.Errorf("unexpected longest ladders in %s graph with %d workers:\ngot: %d %v\nwant:%d %v",
+					test.name, workers, length, ends, longest.value, longest.ends)
+			}
+			width, ends := Widest(g, workers)
+			if width != widest.value || !reflect.DeepEqual(ends, widest.ends) {
+				t.Errorf("unexpected widest ladders in %s graph with %d workers:\ngot: %d %v\nwant:%d %v",
+					test.name, workers, width, ends, widest.value, widest.ends)
+			}
+		}
+	}
+}
+
+func TestWidestSaturates(t *testing.T) {
+	// There are C(2n-2, n-1) shortest paths between
+	// opposite corners of an n×n grid, which is more
+	// than math.MaxInt on 64 bit platforms for n=35.
+	const n = 35
+	g := simple.NewUndirectedGraph()
+	for y := int64(0); y < n; y++ {
+		for x := int64(0); x < n; x++ {
+			u := simple.Node(y*n + x)
+			if x+1 < n {
+				g.SetEdge(simple.Edge{F: u, T: simple.Node(y*n + x + 1)})
+			}
+			if y+1 < n {
+				g.SetEdge(simple.Edge{F: u, T: simple.Node((y+1)*n + x)})
+			}
+		}
+	}
+
+	width, ends := Widest(g, 4)
+	if width != math.MaxInt {
+		t.Errorf("unexpected width of grid: got:%d want:%d", width, math.MaxInt)
+	}
+	for _, corners := range [][2]int64{{0, n*n - 1}, {n - 1, n * (n - 1)}} {
+		var found bool
+		for _, e := range ends {
+			if e == corners {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("missing opposite corners %v from widest pairs", corners)
+		}
+	}
+}
+
+func TestAllTo(t *testing.T) {
+	for _, test := range conformanceTests {
+		g := New(test.kind, test.n, test.opts...)
+		for _, w := range testWords {
+			g.Include(w)
+		}
+		from := g.NodeFor("cold")
+		if from == nil {
+			continue
+		}
+		pth := path.DijkstraAllFrom(from, g)
+		for _, to := range []string{"warm", "cold", "cafe"} {
+			v := g.NodeFor(to)
+			if v == nil {
+				continue
+			}
+			all, _ := pth.AllTo(v.ID())
+			for max := 0; max <= len(all)+1; max++ {
+				want := all
+				if max != 0 && max < len(all) {
+					want = all[:max]
+				}
+				got := AllTo(pth, v.ID(), max)
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("unexpected ladders from cold to %s in %s graph with max=%d:\ngot: %v\nwant:%v",
+						to, test.name, max, got, want)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package wordgraph
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Filter is a stage of a Pipeline that removes words read from a
+// dictionary before they are included in a graph. Words are given to
+// Keep as they are read, before they are converted to lower case.
+type Filter struct {
+	// Name describes the words
+	// removed by the filter.
+	Name string
+
+	// Keep returns whether word
+	// passes through the filter.
+	Keep func(word string) bool
+}
+
+// ProperNouns returns a filter that removes capitalised words, such as
+// "Head" or "Tail", which are taken to be proper nouns. Abbreviations
+// are not removed.
+func ProperNouns() Filter {
+	return Filter{Name: "proper nouns", Keep: func(word string) bool {
+		r, _ := utf8.DecodeRuneInString(word)
+		return !unicode.IsUpper(r) || isAbbreviation(word)
+	}}
+}
+
+// Abbreviations returns a filter that removes abbreviations. Words with
+// more than one upper case letter, such as "NASA" or "PhD", and words
+// ending with a full stop, such as "etc.", are taken to be abbreviations.
+func Abbreviations() Filter {
+	return Filter{Name: "abbreviations", Keep: func(word string) bool {
+		return !isAbbreviation(word)
+	}}
+}
+
+// isAbbreviation returns whether word is an abbreviation.
+func isAbbreviation(word string) bool {
+	if strings.HasSuffix(word, ".") {
+		return true
+	}
+	var upper int
+	for _, r := range word {
+		if unicode.IsUpper(r) {
+			upper++
+		}
+	}
+	return upper > 1
+}
+
+// Blocklist returns a filter that removes the words in the set, which
+// must be held in lower case.
+func Blocklist(words map[string]bool) Filter {
+	return Filter{Name: "blocklist", Keep: func(word string) bool {
+		return !words[strings.ToLower(word)]
+	}}
+}
+
+// Allowlist returns a filter that only keeps the words in the set, which
+// must be held in lower case.
+func Allowlist(words map[string]bool) Filter {
+	return Filter{Name: "allowlist", Keep: func(word string) bool {
+		return words[strings.ToLower(word)]
+	}}
+}
+
+// MinFrequency returns a filter that removes words appearing fewer than
+// n times in a corpus, given the counts of words in the corpus as returned
+// by CountWords.
+func MinFrequency(counts map[string]int, n int) Filter {
+	return Filter{Name: fmt.Sprintf("frequency below %d", n), Keep: func(word string) bool {
+		return counts[strings.ToLower(word)] >= n
+	}}
+}
+
+// ReadWordSet returns the set of words read from r, one word per line,
+// in lower case. Blank lines and lines starting with # are ignored.
+func ReadWordSet(r io.Reader) (map[string]bool, error) {
+	words := make(map[string]bool)
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		words[strings.ToLower(text)] = true
+	}
+	return words, sc.Err()
+}
+
+// Pipeline is a sequence of filters that words pass through in turn. It
+// records the number of words removed by each filter.
+type Pipeline struct {
+	filters []Filter
+	removed []int
+}
+
+// NewPipeline returns a new Pipeline of the given filters.
+func NewPipeline(filters ...Filter) *Pipeline {
+	return &Pipeline{filters: filters, removed: make([]int, len(filters))}
+}
+
+// Wrap returns a function for ReadDictionaries that passes words that
+// are kept by all of the filters to include. Words removed by a filter
+// have the Filtered status.
+func (p *Pipeline) Wrap(include func(word string) Status) func(word string) Status {
+	return func(word string) Status {
+		for i, f := range p.filters {
+			if !f.Keep(word) {
+				p.removed[i]++
+				return Filtered
+			}
+		}
+		return include(word)
+	}
+}
+
+// WriteSummary writes a table of the number of words removed by each
+// filter to w.
+func (p *Pipeline) WriteSummary(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "removed\t  filter")
+	for i, f := range p.filters {
+		fmt.Fprintf(tw, "%d\t  %s\n", p.removed[i], f.Name)
+	}
+	return tw.Flush()
+}
+
+// Source is a source of dictionary words for a command, configured by
+// command line flags. Words are read from the standard input unless
+// dictionary files are given, and are passed through a Pipeline of the
+// requested filters.
+type Source struct {
+	Dictionaries Dictionaries
+
+	ProperNouns   bool
+	Abbreviations bool
+	Blocklist     string
+	Allowlist     string
+	Corpus        string
+	MinFreq       int
+
+	// counts holds the word counts
+	// of the corpus once read.
+	counts map[string]int
+}
+
+// RegisterFlags registers the flags that configure the source in fs.
+func (s *Source) RegisterFlags(fs *flag.FlagSet) {
+	fs.Var(&s.Dictionaries, "dict", "dictionary file to read words from instead of standard input (may be repeated; .gz and .bz2 files are decompressed, and .dic files are read as Hunspell dictionaries)")
+	fs.BoolVar(&s.ProperNouns, "noproper", false, "remove capitalised words taken to be proper nouns from the dictionary")
+	fs.BoolVar(&s.Abbreviations, "noabbrev", false, "remove abbreviations from the dictionary")
+	fs.StringVar(&s.Blocklist, "blocklist", "", "file of words, such as stopwords, to remove from the dictionary")
+	fs.StringVar(&s.Allowlist, "allowlist", "", "file of words to restrict the dictionary to")
+	fs.StringVar(&s.Corpus, "corpus", "", "text file used to count word frequency for -minfreq and word familiarity")
+	fs.IntVar(&s.MinFreq, "minfreq", 0, "remove words appearing fewer times than this in the -corpus text")
+}
+
+// Pipeline returns a Pipeline of the filters requested for the source.
+func (s *Source) Pipeline() (*Pipeline, error) {
+	var filters []Filter
+	if s.ProperNouns {
+		filters = append(filters, ProperNouns())
+	}
+	if s.Abbreviations {
+		filters = append(filters, Abbreviations())
+	}
+	for _, list := range []struct {
+		name   string
+		filter func(map[string]bool) Filter
+	}{
+		{name: s.Blocklist, filter: Blocklist},
+		{name: s.Allowlist, filter: Allowlist},
+	} {
+		if list.name == "" {
+			continue
+		}
+		f, err := OpenDictionary(list.name)
+		if err != nil {
+			return nil, err
+		}
+		words, err := ReadWordSet(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", list.name, err)
+		}
+		filters = append(filters, list.filter(words))
+	}
+	if s.MinFreq > 0 {
+		if s.Corpus == "" {
+			return nil, errors.New("wordgraph: minimum frequency requires a corpus")
+		}
+		counts, err := s.Counts()
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, MinFrequency(counts, s.MinFreq))
+	}
+	return NewPipeline(filters...), nil
+}
+
+// Read calls include with each word read from the source that passes
+// through its filters. If dictionary files or filters are used, the
+// counts of words read from each file and a summary of the words removed
+// by the filters are written to report.
+func (s *Source) Read(include func(word string) Status, report io.Writer) error {
+	p, err := s.Pipeline()
+	if err != nil {
+		return err
+	}
+	names := s.Dictionaries
+	if len(names) == 0 {
+		names = Dictionaries{"-"}
+	}
+	counts, err := ReadDictionaries(names, p.Wrap(include))
+	if err != nil {
+		return err
+	}
+	if len(s.Dictionaries) == 0 && !s.filtered() {
+		return nil
+	}
+	err = WriteCounts(report, counts)
+	if err != nil || !s.filtered() {
+		return err
+	}
+	return p.WriteSummary(report)
+}
+
+// ReadAll returns the words read from the source as a word list. If no
+// dictionary files or filters are used, the word list is the standard
+// input, otherwise it holds the unique words that pass through the filters
+// in lower case, one per line, and a report is written as for Read.
+func (s *Source) ReadAll(report io.Writer) ([]byte, error) {
+	if len(s.Dictionaries) == 0 && !s.filtered() {
+		return io.ReadAll(os.Stdin)
+	}
+	var buf bytes.Buffer
+	err := s.Read(appender(&buf), report)
+	return buf.Bytes(), err
+}
+
+// Configured returns whether any dictionary files, filters or a corpus
+// are requested for the source.
+func (s *Source) Configured() bool {
+	return len(s.Dictionaries) != 0 || s.filtered() || s.Corpus != ""
+}
+
+// Counts returns the number of times each word appears in the corpus of
+// the source. The corpus is read once, and the same counts are returned
+// by later calls. If no corpus is requested, Counts returns nil.
+func (s *Source) Counts() (map[string]int, error) {
+	if s.Corpus == "" || s.counts != nil {
+		return s.counts, nil
+	}
+	counts, err := ReadCorpus(s.Corpus)
+	if err != nil {
+		return nil, err
+	}
+	s.counts = counts
+	return counts, nil
+}
+
+// filtered returns whether any filters are requested for the source.
+func (s *Source) filtered() bool {
+	return s.ProperNouns || s.Abbreviations || s.Blocklist != "" || s.Allowlist != "" || s.MinFreq > 0
+}
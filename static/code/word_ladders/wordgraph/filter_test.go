@@ -0,0 +1,209 @@
+package wordgraph
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// filterWords is a dictionary holding proper nouns and abbreviations.
+var filterWords = []string{"Head", "head", "heal", "NASA", "PhD", "etc.", "Tail", "tail", "the", "Élan", "élan"}
+
+var filterTests = []struct {
+	name   string
+	filter Filter
+	want   []string
+}{
+	{
+		name:   "proper nouns",
+		filter: ProperNouns(),
+		want:   []string{"head", "heal", "NASA", "PhD", "etc.", "tail", "the", "élan"},
+	},
+	{
+		name:   "abbreviations",
+		filter: Abbreviations(),
+		want:   []string{"Head", "head", "heal", "Tail", "tail", "the", "Élan", "élan"},
+	},
+	{
+		name:   "blocklist",
+		filter: Blocklist(map[string]bool{"the": true, "head": true}),
+		want:   []string{"heal", "NASA", "PhD", "etc.", "Tail", "tail", "Élan", "élan"},
+	},
+	{
+		name:   "allowlist",
+		filter: Allowlist(map[string]bool{"the": true, "head": true}),
+		want:   []string{"Head", "head", "the"},
+	},
+	{
+		name:   "frequency",
+		filter: MinFrequency(map[string]int{"head": 3, "heal": 1, "tail": 2}, 2),
+		want:   []string{"Head", "head", "Tail", "tail"},
+	},
+}
+
+func TestFilters(t *testing.T) {
+	for _, test := range filterTests {
+		var got []string
+		for _, w := range filterWords {
+			if test.filter.Keep(w) {
+				got = append(got, w)
+			}
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("unexpected words kept by %s filter:\ngot: %q\nwant:%q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	p := NewPipeline(ProperNouns(), Abbreviations(), Blocklist(map[string]bool{"the": true, "heal": true}))
+	g := New(Lazy, 0)
+	include := p.Wrap(Includer(g))
+	var statuses []Status
+	for _, w := range filterWords {
+		statuses = append(statuses, include(w))
+	}
+	want := []Status{Filtered, Accepted, Filtered, Filtered, Filtered, Filtered, Filtered, Accepted, Filtered, Filtered, Accepted}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Errorf("unexpected statuses:\ngot: %v\nwant:%v", statuses, want)
+	}
+
+	var buf strings.Builder
+	err := p.WriteSummary(&buf)
+	if err != nil {
+		t.Fatalf("failed to write summary: %v", err)
+	}
+	wantSummary := `  removed  filter
+        3  proper nouns
+        3  abbreviations
+        2  blocklist
+`
+	if got := buf.String(); got != wantSummary {
+		t.Errorf("unexpected summary:\ngot:\n%s\nwant:\n%s", got, wantSummary)
+	}
+}
+
+func TestSourceRead(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"words.txt": strings.Join(filterWords, "\n") + "\n",
+		"block.txt": "# stopwords\nthe\n\nHEAL\n",
+		"allow.txt": "head\nheal\ntail\nthe\n",
+		"corpus":    "The head and the tail of the head.",
+	}
+	for name, text := range files {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0o664)
+		if err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	for _, test := range []struct {
+		name string
+		src  Source
+		want []string
+	}{
+		{
+			name: "proper nouns",
+			src:  Source{ProperNouns: true},
+			want: []string{"head", "heal", "nasa", "phd", "tail", "the", "élan"},
+		},
+		{
+			name: "blocklist",
+			src:  Source{ProperNouns: true, Abbreviations: true, Blocklist: filepath.Join(dir, "block.txt")},
+			want: []string{"head", "tail", "élan"},
+		},
+		{
+			name: "allowlist",
+			src:  Source{Allowlist: filepath.Join(dir, "allow.txt")},
+			want: []string{"head", "heal", "tail", "the"},
+		},
+		{
+			name: "frequency",
+			src:  Source{Corpus: filepath.Join(dir, "corpus"), MinFreq: 2},
+			want: []string{"head", "the"},
+		},
+	} {
+		test.src.Dictionaries = Dictionaries{filepath.Join(dir, "words.txt")}
+		g := New(Lazy, 0)
+		var report strings.Builder
+		err := test.src.Read(Includer(g), &report)
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", test.name, err)
+			continue
+		}
+		got := nodeWords(graph.NodesOf(g.Nodes()))
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("unexpected words for %s:\ngot: %q\nwant:%q", test.name, got, test.want)
+		}
+		if !strings.Contains(report.String(), "filter\n") {
+			t.Errorf("missing filter summary for %s:\n%s", test.name, report.String())
+		}
+	}
+
+	_, err := (&Source{MinFreq: 2}).Pipeline()
+	if err == nil {
+		t.Error("expected error for minimum frequency without a corpus")
+	}
+}
+
+func TestSourceConfigured(t *testing.T) {
+	for _, test := range []struct {
+		src  Source
+		want bool
+	}{
+		{src: Source{}, want: false},
+		{src: Source{Dictionaries: Dictionaries{"words.txt"}}, want: true},
+		{src: Source{ProperNouns: true}, want: true},
+		{src: Source{Abbreviations: true}, want: true},
+		{src: Source{Blocklist: "block.txt"}, want: true},
+		{src: Source{Allowlist: "allow.txt"}, want: true},
+		{src: Source{Corpus: "corpus"}, want: true},
+		{src: Source{MinFreq: 2}, want: true},
+	} {
+		if got := test.src.Configured(); got != test.want {
+			t.Errorf("unexpected configuration for %+v: got:%t want:%t", test.src, got, test.want)
+		}
+	}
+}
+
+func TestSourceCounts(t *testing.T) {
+	src := Source{MinFreq: 2}
+	counts, err := src.Counts()
+	if counts != nil || err != nil {
+		t.Errorf("unexpected counts without a corpus: %v %v", counts, err)
+	}
+
+	src.Corpus = filepath.Join(t.TempDir(), "corpus.txt")
+	err = os.WriteFile(src.Corpus, []byte("cold cold warm"), 0o664)
+	if err != nil {
+		t.Fatalf("failed to write corpus: %v", err)
+	}
+	want := map[string]int{"cold": 2, "warm": 1}
+	counts, err = src.Counts()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("unexpected counts:\ngot: %v\nwant:%v", counts, want)
+	}
+
+	// The corpus is only read once, so the counts
+	// are shared by the weights and the filters.
+	err = os.Remove(src.Corpus)
+	if err != nil {
+		t.Fatalf("failed to remove corpus: %v", err)
+	}
+	counts, err = src.Counts()
+	if err != nil || !reflect.DeepEqual(counts, want) {
+		t.Errorf("unexpected counts after first read: %v %v", counts, err)
+	}
+	_, err = src.Pipeline()
+	if err != nil {
+		t.Errorf("unexpected error making pipeline after first read: %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package wordgraph
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/path"
+)
+
+// fuzzLetters is the alphabet that fuzzed words are folded into so
+// that small dictionaries are densely connected.
+var fuzzLetters = []rune("abcdé")
+
+const (
+	maxFuzzWords  = 32
+	maxFuzzLength = 5
+)
+
+// FuzzEagerLazy checks that eager and lazy word graphs built from the same
+// dictionary describe the same graph.
+func FuzzEagerLazy(f *testing.F) {
+	f.Add("cold cord card ward warm word worm wore core care", false)
+	f.Add("cat cot coat cost coast boast boat bat at a", true)
+	f.Add("tool too to toll tall tell ball bell belt bolt", true)
+	f.Add("café cafe caf naïve naive", false)
+
+	f.Fuzz(func(t *testing.T, text string, edit bool) {
+		words := fuzzWords(text)
+		if len(words) == 0 {
+			return
+		}
+		var opts []Option
+		n := utf8.RuneCountInString(words[0])
+		if edit {
+			n = 0
+			opts = append(opts, Levenshtein())
+		}
+		eager := New(Eager, n, opts...)
+		lazy := New(Lazy, n, opts...)
+		for _, w := range words {
+			eager.Include(w)
+			lazy.Include(w)
+		}
+
+		nodes := nodeWords(graph.NodesOf(eager.Nodes()))
+		if got := nodeWords(graph.NodesOf(lazy.Nodes())); !reflect.DeepEqual(got, nodes) {
+			t.Fatalf("node sets differ for %q edit=%t:\neager:%v\nlazy: %v", words, edit, nodes, got)
+		}
+
+		for _, u := range graph.NodesOf(eager.Nodes()) {
+			uid := lazy.NodeFor(u.(node).word).ID()
+			want := nodeWords(graph.NodesOf(eager.From(u.ID())))
+			got := nodeWords(graph.NodesOf(lazy.From(uid)))
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("neighbour sets of %q differ for %q edit=%t:\neager:%v\nlazy: %v", u, words, edit, want, got)
+			}
+
+			eagerPaths := path.DijkstraAllFrom(u, eager)
+			lazyPaths := path.DijkstraAllFrom(lazy.NodeFor(u.(node).word), lazy)
+			for _, v := range graph.NodesOf(eager.Nodes()) {
+				want, wantLen := eagerPaths.AllTo(v.ID())
+				got, gotLen := lazyPaths.AllTo(lazy.NodeFor(v.(node).word).ID())
+				if gotLen != wantLen {
+					t.Errorf("ladder lengths from %q to %q differ for %q edit=%t: eager:%v lazy:%v", u, v, words, edit, wantLen, gotLen)
+				}
+				if !reflect.DeepEqual(ladderWords(got), ladderWords(want)) {
+					t.Errorf("ladders from %q to %q differ for %q edit=%t:\neager:%v\nlazy: %v", u, v, words, edit, want, got)
+				}
+			}
+		}
+	})
+}
+
+// fuzzWords returns the words of text folded into fuzzLetters, keeping
+// at most maxFuzzWords words of at most maxFuzzLength letters.
+func fuzzWords(text string) []string {
+	var words []string
+	for _, f := range strings.FieldsFunc(text, func(r rune) bool { return !unicode.IsLetter(r) }) {
+		r := []rune(f)
+		if len(r) > maxFuzzLength {
+			continue
+		}
+		for i, c := range r {
+			r[i] = fuzzLetters[int(unicode.ToLower(c))%len(fuzzLetters)]
+		}
+		words = append(words, string(r))
+		if len(words) == maxFuzzWords {
+			break
+		}
+	}
+	return words
+}
+
+// nodeWords returns the sorted words of nodes.
+func nodeWords(nodes []graph.Node) []string {
+	words := make([]string, len(nodes))
+	for i, n := range nodes {
+		words[i] = n.(node).word
+	}
+	sort.Strings(words)
+	return words
+}
+
+// ladderWords returns the sorted ladders, with the words of each ladder
+// joined by spaces.
+func ladderWords(ladders [][]graph.Node) []string {
+	var words []string
+	for _, l := range ladders {
+		var b strings.Builder
+		for i, n := range l {
+			if i != 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteString(n.(node).word)
+		}
+		words = append(words, b.String())
+	}
+	sort.Strings(words)
+	return words
+}
@@ -0,0 +1,438 @@
+package wordgraph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ReadHunspell returns the words of the Hunspell dictionary read from dic,
+// expanded by the prefix and suffix rules of the affix file read from aff.
+// Words are returned in the order they are generated, without duplicates.
+//
+// Roots marked with the FORBIDDENWORD flag are not returned, even if they
+// are generated from other roots. Entries with the NOSUGGEST or
+// ONLYINCOMPOUND flags are skipped, but the words they would generate are
+// returned if other entries, such as homographs, generate them. Roots
+// marked with the NEEDAFFIX flag are only returned with an affix. Compound words are not generated, and the only
+// supported encodings are UTF-8 and ISO8859-1.
+func ReadHunspell(dic, aff io.Reader) ([]string, error) {
+	h, err := readAffixes(aff)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		words     []string
+		seen      = make(map[string]bool)
+		forbidden = make(map[string]bool)
+	)
+	emit := func(w string) {
+		if !seen[w] {
+			seen[w] = true
+			words = append(words, w)
+		}
+	}
+	sc := bufio.NewScanner(dic)
+	for line := 1; sc.Scan(); line++ {
+		text := h.decode(sc.Bytes())
+		if line == 1 {
+			// The first line holds the approximate
+			// number of words in the dictionary.
+			text = strings.TrimPrefix(text, "\ufeff")
+			if _, err := strconv.Atoi(strings.TrimSpace(text)); err == nil {
+				continue
+			}
+		}
+		root, flags, err := h.entry(text)
+		if err != nil {
+			return nil, fmt.Errorf("wordgraph: dic line %d: %w", line, err)
+		}
+		if root == "" {
+			continue
+		}
+		switch {
+		case hasFlag(flags, h.forbidden):
+			forbidden[root] = true
+		case hasFlag(flags, h.noSuggest), hasFlag(flags, h.onlyInCompound):
+			continue
+		default:
+			h.expand(root, flags, emit)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	n := 0
+	for _, w := range words {
+		if !forbidden[w] {
+			words[n] = w
+			n++
+		}
+	}
+	return words[:n], nil
+}
+
+// hunspell holds the affix rules of a Hunspell dictionary.
+type hunspell struct {
+	// latin1 is whether the dictionary is
+	// encoded as ISO8859-1 rather than UTF-8.
+	latin1 bool
+
+	// flagType is the encoding of flags, one of
+	// "" for single characters, "long" for pairs
+	// of characters, "num" for comma-separated
+	// numbers, or "UTF-8".
+	flagType string
+
+	// aliases holds flag sets that are referred
+	// to by their one-based index in place of
+	// the flags themselves.
+	aliases [][]string
+
+	prefixes map[string][]affix
+	suffixes map[string][]affix
+
+	// crosses records which affix flags may
+	// be combined with affixes of the other
+	// kind.
+	crosses map[string]bool
+
+	forbidden      string
+	noSuggest      string
+	needAffix      string
+	onlyInCompound string
+}
+
+// affix is a prefix or suffix rule.
+type affix struct {
+	flag   string
+	suffix bool
+
+	// strip is removed from the word and
+	// add put in its place, when the word
+	// matches the condition.
+	strip string
+	add   string
+	cond  []class
+
+	// cont holds the continuation flags
+	// of affixes that may follow this one.
+	cont []string
+}
+
+// class is a character class of an affix condition.
+type class struct {
+	any    bool
+	negate bool
+	runes  string
+}
+
+// match returns whether r is in the class.
+func (c class) match(r rune) bool {
+	if c.any {
+		return true
+	}
+	return strings.ContainsRune(c.runes, r) != c.negate
+}
+
+// readAffixes returns the rules of the affix file read from r.
+func readAffixes(r io.Reader) (*hunspell, error) {
+	h := &hunspell{
+		prefixes: make(map[string][]affix),
+		suffixes: make(map[string][]affix),
+		crosses:  make(map[string]bool),
+	}
+	headers := make(map[string]bool)
+	var aliasCount bool
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		text := h.decode(sc.Bytes())
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		f := strings.Fields(text)
+		if len(f) < 2 || strings.HasPrefix(f[0], "#") {
+			continue
+		}
+		switch f[0] {
+		case "SET":
+			switch strings.ToUpper(f[1]) {
+			case "UTF-8":
+				h.latin1 = false
+			case "ISO8859-1", "ISO-8859-1":
+				h.latin1 = true
+			default:
+				return nil, fmt.Errorf("wordgraph: aff line %d: unsupported encoding %q", line, f[1])
+			}
+		case "FLAG":
+			switch f[1] {
+			case "long", "num", "UTF-8":
+				h.flagType = f[1]
+			default:
+				return nil, fmt.Errorf("wordgraph: aff line %d: unknown flag type %q", line, f[1])
+			}
+		case "AF":
+			// The first AF line holds the number of aliases.
+			if !aliasCount {
+				aliasCount = true
+				continue
+			}
+			h.aliases = append(h.aliases, h.flags(f[1]))
+		case "FORBIDDENWORD":
+			h.forbidden = f[1]
+		case "NOSUGGEST":
+			h.noSuggest = f[1]
+		case "NEEDAFFIX", "PSEUDOROOT":
+			h.needAffix = f[1]
+		case "ONLYINCOMPOUND":
+			h.onlyInCompound = f[1]
+		case "PFX", "SFX":
+			key := f[0] + " " + f[1]
+			if !headers[key] {
+				// The first line of an affix class holds
+				// whether it may be combined with affixes
+				// of the other kind and the number of rules.
+				if len(f) < 4 {
+					return nil, fmt.Errorf("wordgraph: aff line %d: malformed affix header", line)
+				}
+				headers[key] = true
+				h.crosses[key] = f[2] == "Y"
+				continue
+			}
+			if len(f) < 4 {
+				return nil, fmt.Errorf("wordgraph: aff line %d: malformed affix rule", line)
+			}
+			a, err := h.affix(f)
+			if err != nil {
+				return nil, fmt.Errorf("wordgraph: aff line %d: %w", line, err)
+			}
+			if a.suffix {
+				h.suffixes[a.flag] = append(h.suffixes[a.flag], a)
+			} else {
+				h.prefixes[a.flag] = append(h.prefixes[a.flag], a)
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// affix returns the affix rule held in the fields of an affix line.
+func (h *hunspell) affix(f []string) (affix, error) {
+	a := affix{flag: f[1], suffix: f[0] == "SFX"}
+	if f[2] != "0" {
+		a.strip = f[2]
+	}
+	add := f[3]
+	if i := strings.Index(add, "/"); i >= 0 {
+		var err error
+		a.cont, err = h.flagsOrAlias(add[i+1:])
+		if err != nil {
+			return a, err
+		}
+		add = add[:i]
+	}
+	if add != "0" {
+		a.add = add
+	}
+	cond := "."
+	if len(f) > 4 {
+		cond = f[4]
+	}
+	var err error
+	a.cond, err = parseCondition(cond)
+	return a, err
+}
+
+// parseCondition returns the character classes of an affix condition.
+func parseCondition(cond string) ([]class, error) {
+	var classes []class
+	for i := 0; i < len(cond); {
+		switch cond[i] {
+		case '.':
+			classes = append(classes, class{any: true})
+			i++
+		case '[':
+			end := strings.IndexByte(cond[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated character class in condition %q", cond)
+			}
+			c := class{runes: cond[i+1 : i+end]}
+			if strings.HasPrefix(c.runes, "^") {
+				c.negate = true
+				c.runes = c.runes[1:]
+			}
+			classes = append(classes, c)
+			i += end + 1
+		default:
+			r, size := utf8.DecodeRuneInString(cond[i:])
+			classes = append(classes, class{runes: string(r)})
+			i += size
+		}
+	}
+	return classes, nil
+}
+
+// apply returns the word formed by applying the affix to w, and whether
+// the affix can be applied.
+func (a affix) apply(w string) (string, bool) {
+	r := []rune(w)
+	if len(a.cond) > len(r) {
+		return "", false
+	}
+	if a.suffix {
+		if !strings.HasSuffix(w, a.strip) {
+			return "", false
+		}
+		for i, c := range a.cond {
+			if !c.match(r[len(r)-len(a.cond)+i]) {
+				return "", false
+			}
+		}
+		w = w[:len(w)-len(a.strip)] + a.add
+	} else {
+		if !strings.HasPrefix(w, a.strip) {
+			return "", false
+		}
+		for i, c := range a.cond {
+			if !c.match(r[i]) {
+				return "", false
+			}
+		}
+		w = a.add + w[len(a.strip):]
+	}
+	return w, w != ""
+}
+
+// expand calls emit with the root and each word generated from it by the
+// affixes with the given flags.
+func (h *hunspell) expand(root string, flags []string, emit func(string)) {
+	if !hasFlag(flags, h.needAffix) {
+		emit(root)
+	}
+	for _, f := range flags {
+		for _, p := range h.prefixes[f] {
+			if w, ok := p.apply(root); ok && !hasFlag(p.cont, h.needAffix) {
+				emit(w)
+			}
+		}
+	}
+	for _, f := range flags {
+		for _, s := range h.suffixes[f] {
+			w, ok := s.apply(root)
+			if !ok {
+				continue
+			}
+			if !hasFlag(s.cont, h.needAffix) {
+				emit(w)
+			}
+
+			// Apply suffixes that may follow this one.
+			for _, cf := range s.cont {
+				for _, s2 := range h.suffixes[cf] {
+					if w2, ok := s2.apply(w); ok {
+						emit(w2)
+					}
+				}
+			}
+
+			// Combine with prefixes that allow it.
+			if !h.crosses["SFX "+f] {
+				continue
+			}
+			for _, pf := range append(flags[:len(flags):len(flags)], s.cont...) {
+				if !h.crosses["PFX "+pf] {
+					continue
+				}
+				for _, p := range h.prefixes[pf] {
+					if w2, ok := p.apply(w); ok {
+						emit(w2)
+					}
+				}
+			}
+		}
+	}
+}
+
+// entry returns the root word and flags of a dictionary line. Any
+// morphological fields following the word are ignored.
+func (h *hunspell) entry(text string) (root string, flags []string, err error) {
+	if i := strings.IndexAny(text, " \t"); i >= 0 {
+		text = text[:i]
+	}
+	// Find the first slash that is not escaped.
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '/':
+			flags, err = h.flagsOrAlias(text[i+1:])
+			return strings.ReplaceAll(text[:i], `\/`, "/"), flags, err
+		}
+	}
+	return strings.ReplaceAll(text, `\/`, "/"), nil, nil
+}
+
+// flagsOrAlias returns the flags held in s, which is an alias index if
+// the affix file defines flag aliases.
+func (h *hunspell) flagsOrAlias(s string) ([]string, error) {
+	if h.aliases == nil {
+		return h.flags(s), nil
+	}
+	i, err := strconv.Atoi(s)
+	if err != nil || i < 1 || i > len(h.aliases) {
+		return nil, fmt.Errorf("invalid flag alias %q", s)
+	}
+	return h.aliases[i-1], nil
+}
+
+// flags returns the flags held in s.
+func (h *hunspell) flags(s string) []string {
+	var flags []string
+	switch h.flagType {
+	case "num":
+		flags = strings.Split(s, ",")
+	case "long":
+		r := []rune(s)
+		for i := 0; i+1 < len(r); i += 2 {
+			flags = append(flags, string(r[i:i+2]))
+		}
+	default:
+		for _, r := range s {
+			flags = append(flags, string(r))
+		}
+	}
+	return flags
+}
+
+// hasFlag returns whether flags holds flag.
+func hasFlag(flags []string, flag string) bool {
+	if flag == "" {
+		return false
+	}
+	for _, f := range flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// decode returns the text of a line in the dictionary's encoding.
+func (h *hunspell) decode(b []byte) string {
+	if !h.latin1 {
+		return string(b)
+	}
+	r := make([]rune, len(b))
+	for i, c := range b {
+		r[i] = rune(c)
+	}
+	return string(r)
+}
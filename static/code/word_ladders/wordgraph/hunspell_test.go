@@ -0,0 +1,197 @@
+package wordgraph
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var hunspellTests = []struct {
+	name string
+	aff  string
+	dic  string
+	want []string
+}{
+	{
+		name: "suffixes",
+		aff: `SET UTF-8
+
+SFX S Y 3
+SFX S   y     ies        [^aeiou]y
+SFX S   0     s          [aeiou]y
+SFX S   0     s          [^y]
+
+SFX D Y 2
+SFX D   0     d          e
+SFX D   0     ed         [^e]
+`,
+		dic: `4
+cord/SD
+cure/SD
+ply/S
+toy/S
+`,
+		want: []string{"cord", "cords", "corded", "cure", "cures", "cured", "ply", "plies", "toy", "toys"},
+	},
+	{
+		name: "cross product",
+		aff: `PFX U Y 1
+PFX U   0     un         .
+
+PFX R N 1
+PFX R   0     re         .
+
+SFX D Y 1
+SFX D   0     ed         .
+`,
+		dic: `2
+lock/UD
+load/RD
+`,
+		want: []string{"lock", "unlock", "locked", "unlocked", "load", "reload", "loaded"},
+	},
+	{
+		name: "continuation",
+		aff: `SFX A Y 1
+SFX A   0     able/S     .
+
+SFX S Y 1
+SFX S   0     s          .
+`,
+		dic: `1
+read/A
+`,
+		want: []string{"read", "readable", "readables"},
+	},
+	{
+		name: "flags",
+		aff: `FORBIDDENWORD !
+NOSUGGEST N
+NEEDAFFIX X
+ONLYINCOMPOUND C
+
+SFX S Y 1
+SFX S   0     s          .
+`,
+		dic: `6
+bolt/S
+bolts/!
+darn/NS
+torn/XS
+part/C
+ward
+`,
+		want: []string{"bolt", "torns", "ward"},
+	},
+	{
+		name: "homographs",
+		aff: `NOSUGGEST !
+
+SFX S Y 1
+SFX S   0     s          .
+`,
+		dic: `2
+cock/!
+cock/S
+`,
+		want: []string{"cock", "cocks"},
+	},
+	{
+		name: "long flags and aliases",
+		aff: `FLAG long
+AF 2
+AF Sx
+AF SxDx
+
+SFX Sx Y 1
+SFX Sx  0     s          .
+
+SFX Dx Y 1
+SFX Dx  0     ed         .
+`,
+		dic: `2
+cold/1
+warm/2	po:adj
+`,
+		want: []string{"cold", "colds", "warm", "warms", "warmed"},
+	},
+	{
+		name: "numeric flags",
+		aff: `FLAG num
+
+SFX 12 Y 1
+SFX 12  0     s          .
+
+SFX 345 Y 1
+SFX 345 0     ed         .
+`,
+		dic: `1
+word/12,345
+`,
+		want: []string{"word", "words", "worded"},
+	},
+	{
+		name: "latin1",
+		aff:  "SET ISO8859-1\n\nSFX S Y 1\nSFX S   0     s          [^\xe9]\n",
+		dic:  "2\ncaf\xe9/S\nna\xefve/S\n",
+		want: []string{"café", "naïve", "naïves"},
+	},
+}
+
+func TestReadHunspell(t *testing.T) {
+	for _, test := range hunspellTests {
+		got, err := ReadHunspell(strings.NewReader(test.dic), strings.NewReader(test.aff))
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", test.name, err)
+			continue
+		}
+		sort.Strings(got)
+		want := append([]string(nil), test.want...)
+		sort.Strings(want)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("unexpected words for %s:\ngot: %q\nwant:%q", test.name, got, want)
+		}
+	}
+}
+
+func TestReadHunspellErrors(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		aff  string
+		dic  string
+	}{
+		{name: "encoding", aff: "SET KOI8-R\n", dic: "1\nword\n"},
+		{name: "flag type", aff: "FLAG short\n", dic: "1\nword\n"},
+		{name: "condition", aff: "SFX S Y 1\nSFX S 0 s [ab\n", dic: "1\nword/S\n"},
+		{name: "alias", aff: "AF 1\nAF S\n", dic: "1\nword/2\n"},
+	} {
+		_, err := ReadHunspell(strings.NewReader(test.dic), strings.NewReader(test.aff))
+		if err == nil {
+			t.Errorf("expected error for invalid %s", test.name)
+		}
+	}
+}
+
+func TestReadDictionariesHunspell(t *testing.T) {
+	dir := t.TempDir()
+	test := hunspellTests[0]
+	for _, f := range []struct{ ext, text string }{{".aff", test.aff}, {".dic", test.dic}} {
+		err := os.WriteFile(filepath.Join(dir, "en"+f.ext), []byte(f.text), 0o664)
+		if err != nil {
+			t.Fatalf("failed to write dictionary: %v", err)
+		}
+	}
+
+	g := New(Lazy, 4)
+	counts, err := ReadDictionaries([]string{filepath.Join(dir, "en.dic")}, Includer(g))
+	if err != nil {
+		t.Fatalf("failed to read dictionary: %v", err)
+	}
+	want := []Counts{{Name: filepath.Join(dir, "en.dic"), Accepted: 3, Rejected: 7}}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("unexpected counts:\ngot: %+v\nwant:%+v", counts, want)
+	}
+}
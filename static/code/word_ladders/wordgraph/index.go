@@ -0,0 +1,200 @@
+package wordgraph
+
+import (
+	"math"
+	"unicode/utf8"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// wildcard is the placeholder for a letter in a word pattern. It is
+// not a letter, so patterns can not collide with words.
+const wildcard = '_'
+
+// index is a word list that groups words by their single-wildcard
+// patterns. A word of n letters is held in n buckets, each keyed by
+// the word with one letter replaced by the wildcard, so words that
+// are Hamming distance one apart share exactly one bucket. Words a
+// single letter insertion apart are found by scanning the buckets
+// keyed by the shorter word with a wildcard inserted.
+type index struct {
+	words   []string
+	ids     map[string]int64
+	buckets map[string][]int64
+}
+
+// newIndex returns a new empty index.
+func newIndex() index {
+	return index{
+		ids:     make(map[string]int64),
+		buckets: make(map[string][]int64),
+	}
+}
+
+// add adds word to the index and returns its ID. The word must not
+// already be in the index.
+func (idx *index) add(word string) int64 {
+	id := int64(len(idx.words))
+	idx.ids[word] = id
+	idx.words = append(idx.words, word)
+	r := []rune(word)
+	for j := range r {
+		p := pattern(r, j, false)
+		idx.buckets[p] = append(idx.buckets[p], id)
+	}
+	return id
+}
+
+// heuristicCost returns the distance between the words held by the nodes
+// x and y. If edit is true the Levenshtein distance is returned, otherwise
+// the Hamming distance is returned, or positive infinity for words with
+// different lengths.
+func (idx index) heuristicCost(x, y graph.Node, edit bool) float64 {
+	u := idx.words[x.ID()]
+	v := idx.words[y.ID()]
+	if edit {
+		return float64(EditDistance(u, v))
+	}
+	if utf8.RuneCountInString(u) != utf8.RuneCountInString(v) {
+		return math.Inf(1)
+	}
+	return float64(HammingDistance(u, v))
+}
+
+// pattern returns the word with the letter at position j replaced by
+// the wildcard, or if insert is true, with the wildcard inserted before
+// the letter at position j.
+func pattern(word []rune, j int, insert bool) string {
+	k := j
+	if !insert {
+		k++
+	}
+	return string(word[:j]) + string(wildcard) + string(word[k:])
+}
+
+// runeAt returns the letter at position j of word.
+func runeAt(word string, j int) rune {
+	for _, c := range word {
+		if j == 0 {
+			return c
+		}
+		j--
+	}
+	panic("wordgraph: letter position out of range")
+}
+
+// neighbours implements the graph.Nodes interface. It is a deterministic
+// iterator over sets of nodes that represent words with distance-1
+// from a query word.
+type neighbours struct {
+	idx  index
+	id   int64
+	word []rune
+	edit bool
+
+	op     editOp
+	j      int
+	bucket []int64
+	k      int
+
+	curr graph.Node
+}
+
+// editOp is a single letter edit made to a word.
+type editOp int
+
+const (
+	substitution editOp = iota
+	deletion
+	insertion
+)
+
+// newNeighbours returns a new word neighbours iterator for the word in idx
+// with the given ID. If edit is true the iterator includes words a single
+// letter insertion or deletion from the word.
+func newNeighbours(idx index, id int64, edit bool) *neighbours {
+	return &neighbours{idx: idx, id: id, word: []rune(idx.words[id]), edit: edit}
+}
+
+// Len implements the graph.Nodes Len method. It returns -1 to indicate the iterator
+// has an unknown number of of items.
+func (it *neighbours) Len() int { return -1 }
+
+// Next implements the graph.Nodes Next method.
+func (it *neighbours) Next() bool {
+	for it.op == substitution {
+		for it.k < len(it.bucket) {
+			vid := it.bucket[it.k]
+			it.k++
+			if vid == it.id {
+				continue
+			}
+			// We have found a neighbouring word so we can return
+			// true and set the current word to this neighbour.
+			it.curr = node{word: it.idx.words[vid], id: vid}
+			return true
+		}
+		if it.j == len(it.word) {
+			if !it.edit {
+				it.curr = nil
+				return false
+			}
+			it.op, it.j = deletion, 0
+			break
+		}
+		it.bucket, it.k = it.idx.buckets[pattern(it.word, it.j, false)], 0
+		it.j++
+	}
+
+	for it.op == deletion && it.j < len(it.word) {
+		j := it.j
+		it.j++
+
+		// Deleting any letter from a run of repeated letters gives
+		// the same word, so only delete the first letter of a run.
+		if j > 0 && it.word[j-1] == it.word[j] {
+			continue
+		}
+		w := string(it.word[:j]) + string(it.word[j+1:])
+		if vid, ok := it.idx.ids[w]; ok {
+			it.curr = node{word: w, id: vid}
+			return true
+		}
+	}
+	if it.op == deletion {
+		it.op, it.j, it.bucket, it.k = insertion, 0, nil, 0
+	}
+
+	for {
+		for it.k < len(it.bucket) {
+			vid := it.bucket[it.k]
+			it.k++
+
+			// Inserting a letter next to the same letter gives the
+			// same word, so only insert after a run of that letter.
+			// The wildcard of the current bucket is at it.j-1.
+			j := it.j - 1
+			v := it.idx.words[vid]
+			if j < len(it.word) && runeAt(v, j) == it.word[j] {
+				continue
+			}
+			it.curr = node{word: v, id: vid}
+			return true
+		}
+		if it.j > len(it.word) {
+			it.curr = nil
+			return false
+		}
+		it.bucket, it.k = it.idx.buckets[pattern(it.word, it.j, true)], 0
+		it.j++
+	}
+}
+
+// Node implements the graph.Nodes Node method.
+func (it *neighbours) Node() graph.Node { return it.curr }
+
+// Reset implements the graph.Nodes Reset method.
+func (it *neighbours) Reset() {
+	it.op, it.j, it.bucket, it.k = substitution, 0, nil, 0
+	it.curr = nil
+}
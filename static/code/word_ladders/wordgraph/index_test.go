@@ -0,0 +1,68 @@
+package wordgraph
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestNeighbours(t *testing.T) {
+	for _, edit := range []bool{false, true} {
+		idx := newIndex()
+		for _, w := range testWords {
+			idx.add(w)
+		}
+		for id, u := range idx.words {
+			var want []string
+			for _, v := range idx.words {
+				if u != v && joined(u, v, edit) {
+					want = append(want, v)
+				}
+			}
+			sort.Strings(want)
+
+			it := newNeighbours(idx, int64(id), edit)
+			if it.Len() != -1 {
+				t.Errorf("unexpected length for %q edit=%t: got:%d want:-1", u, edit, it.Len())
+			}
+			first := neighbourWords(it)
+			if it.Node() != nil {
+				t.Errorf("unexpected node after exhausting iterator for %q edit=%t: got:%v", u, edit, it.Node())
+			}
+			if it.Next() {
+				t.Errorf("unexpected next node after exhausting iterator for %q edit=%t: got:%v", u, edit, it.Node())
+			}
+			got := append([]string(nil), first...)
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("unexpected neighbours for %q edit=%t:\ngot: %v\nwant:%v", u, edit, got, want)
+			}
+
+			// Check that Reset restarts the iteration from
+			// every point part way through it.
+			for steps := 0; steps <= len(first); steps++ {
+				it.Reset()
+				for i := 0; i < steps; i++ {
+					it.Next()
+				}
+				it.Reset()
+				if it.Node() != nil {
+					t.Errorf("unexpected node after reset for %q edit=%t: got:%v", u, edit, it.Node())
+				}
+				again := neighbourWords(it)
+				if !reflect.DeepEqual(again, first) {
+					t.Errorf("unexpected neighbours after reset for %q edit=%t:\ngot: %v\nwant:%v", u, edit, again, first)
+				}
+			}
+		}
+	}
+}
+
+// neighbourWords returns the words of the remaining nodes in it.
+func neighbourWords(it *neighbours) []string {
+	var words []string
+	for it.Next() {
+		words = append(words, it.Node().(node).word)
+	}
+	return words
+}
@@ -0,0 +1,189 @@
+package wordgraph
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// InvalidWord is the error returned when the first or last word of a
+// ladder cannot be included in a word graph.
+type InvalidWord struct {
+	Word string
+
+	// Alphabet is whether the word holds letters
+	// that are not in the alphabet of the graph,
+	// rather than punctuation or numerals.
+	Alphabet bool
+}
+
+func (e *InvalidWord) Error() string {
+	if e.Alphabet {
+		return fmt.Sprintf("word must only contain letters in the alphabet: %q", e.Word)
+	}
+	return fmt.Sprintf("word must not contain punctuation or numerals: %q", e.Word)
+}
+
+// ReadLadder reads the words of the source into g to find ladders between
+// first and last, and returns first and last in lower case. The first and
+// last words are included in g before the dictionary is read, so ladders
+// can be found between words that are not in the dictionary. Words that
+// are not in the dictionary are written to report as warnings suggesting
+// similar words that are, or in strict mode are returned as NotInDictionary
+// errors. An InvalidWord error is returned if first or last cannot be
+// included in g.
+func (s *Source) ReadLadder(g Graph, first, last string, strict bool, report io.Writer) (string, string, error) {
+	words := []string{first, last}
+	for i, w := range words {
+		lw := strings.ToLower(w)
+		if !IsWord(lw) {
+			return "", "", &InvalidWord{Word: w}
+		}
+		g.Include(lw)
+		if g.NodeFor(lw) == nil {
+			return "", "", &InvalidWord{Word: lw, Alphabet: true}
+		}
+		words[i] = lw
+	}
+
+	ends := NewEndpoints(words...)
+	err := s.Read(ends.Wrap(Includer(g)), report)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read word list: %w", err)
+	}
+	if strict {
+		missing := ends.Check(g)
+		if len(missing) != 0 {
+			return "", "", errors.Join(missing...)
+		}
+	} else {
+		ends.Report(report, g, false)
+	}
+	return words[0], words[1], nil
+}
+
+// GraphSource is a source of the graph searched by a command, configured
+// by command line flags. The graph is read from an edge list or DOT file,
+// held in a cache of the dictionary words of the Source, or is a LazyGraph
+// of the dictionary words.
+type GraphSource struct {
+	Source
+
+	Alphabet string
+	Graph    string
+	Cache    string
+
+	cache *Cache
+}
+
+// RegisterFlags registers the -alphabet, -graph and -cache flags, and the
+// flags of the Source, with fs.
+func (s *GraphSource) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&s.Alphabet, "alphabet", "", "letters that may be used in words (default inferred from the word list)")
+	fs.StringVar(&s.Graph, "graph", "", "edge list or DOT (.dot or .gv) file to read the graph from instead of a word list")
+	fs.StringVar(&s.Cache, "cache", "", "file holding a graph cache of the word list, built if missing or stale")
+	s.Source.RegisterFlags(fs)
+}
+
+// Valid returns whether the flags of the source can be used together. A
+// cache can not be used with a graph file or an alphabet, and a graph file
+// can not be used with an alphabet or any dictionary flags.
+func (s *GraphSource) Valid() bool {
+	if s.Cache != "" && (s.Graph != "" || s.Alphabet != "") {
+		return false
+	}
+	return s.Graph == "" || (s.Alphabet == "" && !s.Source.Configured())
+}
+
+// Load returns the graph of the source. Unless the graph is read from a
+// graph file, it holds the dictionary words with n letters.
+func (s *GraphSource) Load(n int, report io.Writer) (Graph, error) {
+	switch {
+	case s.Graph != "":
+		// Use the edges of the graph file in place
+		// of edges implied by word distance.
+		g, err := ReadGraphFile(s.Graph)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read graph: %w", err)
+		}
+		return g, nil
+	case s.Cache != "":
+		return s.loadCache(n, report)
+	default:
+		g := New(Lazy, n, Alphabet(s.Alphabet))
+		err := s.Read(Includer(g), report)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read word list: %w", err)
+		}
+		return g, nil
+	}
+}
+
+// LoadLadder returns the graph of the source for finding ladders between
+// first and last, and first and last as they are held in the graph. The
+// graphs of graph files and caches can not include new words, so first
+// and last must be nodes of a graph file and must be in the dictionary of
+// a cache. Otherwise the words are included in a LazyGraph of the dictionary
+// words as described for Source.ReadLadder.
+func (s *GraphSource) LoadLadder(first, last string, strict bool, report io.Writer) (g Graph, f, l string, err error) {
+	n := utf8.RuneCountInString(first)
+	switch {
+	case s.Graph != "":
+		g, err = s.Load(n, report)
+		if err != nil {
+			return nil, "", "", err
+		}
+		for _, w := range []string{first, last} {
+			if g.NodeFor(w) == nil {
+				return nil, "", "", fmt.Errorf("node must be in the graph: %q", w)
+			}
+		}
+		return g, first, last, nil
+	case s.Cache != "":
+		cg, err := s.loadCache(n, report)
+		if err != nil {
+			return nil, "", "", err
+		}
+		f, l = strings.ToLower(first), strings.ToLower(last)
+		for _, w := range []string{f, l} {
+			if cg.NodeFor(w) == nil {
+				return nil, "", "", &NotInDictionary{Word: w, Suggestions: Suggest(cg, w, MaxSuggestions)}
+			}
+		}
+		return cg, f, l, nil
+	default:
+		g = New(Lazy, n, Alphabet(s.Alphabet))
+		f, l, err = s.ReadLadder(g, first, last, strict, report)
+		if err != nil {
+			return nil, "", "", err
+		}
+		return g, f, l, nil
+	}
+}
+
+// loadCache returns the graph of words with n letters held in the cache
+// of the source, building the cache from the dictionary if necessary.
+func (s *GraphSource) loadCache(n int, report io.Writer) (*CachedGraph, error) {
+	dict, err := s.ReadAll(report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read word list: %w", err)
+	}
+	c, err := LoadCache(s.Cache, dict)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cache: %w", err)
+	}
+	s.cache = c
+	return c.Graph(n), nil
+}
+
+// Close releases the cache of the source, if it was loaded. Graphs held
+// in the cache must not be used after Close has been called.
+func (s *GraphSource) Close() error {
+	if s.cache == nil {
+		return nil
+	}
+	return s.cache.Close()
+}
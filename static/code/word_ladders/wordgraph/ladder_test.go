@@ -0,0 +1,213 @@
+package wordgraph
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+func TestReadLadder(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "words.txt")
+	err := os.WriteFile(name, testDictionary(), 0o664)
+	if err != nil {
+		t.Fatalf("failed to write dictionary: %v", err)
+	}
+	src := Source{Dictionaries: Dictionaries{name}}
+
+	for _, test := range []struct {
+		first, last string
+		strict      bool
+		alphabet    string
+
+		wantFirst, wantLast string
+		wantErr             string
+		wantWarning         string
+	}{
+		{
+			first: "Cold", last: "WARM",
+			wantFirst: "cold", wantLast: "warm",
+		},
+		{
+			first: "wrod", last: "warm",
+			wantFirst: "wrod", wantLast: "warm",
+			wantWarning: `warning: word not in dictionary: "wrod" (did you mean "ward", "word"?)`,
+		},
+		{
+			first: "wrod", last: "wqrm", strict: true,
+			wantErr: `word not in dictionary: "wrod" (did you mean "ward", "word"?)
+word not in dictionary: "wqrm" (did you mean "warm", "worm", "ward", "word", "wore"?)`,
+		},
+		{
+			first: "c0ld", last: "warm",
+			wantErr: `word must not contain punctuation or numerals: "c0ld"`,
+		},
+		{
+			first: "cold", last: "WARM", alphabet: "cdlo",
+			wantErr: `word must only contain letters in the alphabet: "warm"`,
+		},
+	} {
+		g := New(Lazy, 4, Alphabet(test.alphabet))
+		var report strings.Builder
+		first, last, err := src.ReadLadder(g, test.first, test.last, test.strict, &report)
+		if test.wantErr != "" {
+			if err == nil || err.Error() != test.wantErr {
+				t.Errorf("unexpected error for %s to %s:\ngot: %v\nwant:%s", test.first, test.last, err, test.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for %s to %s: %v", test.first, test.last, err)
+			continue
+		}
+		if first != test.wantFirst || last != test.wantLast {
+			t.Errorf("unexpected ladder words for %s to %s: got:%s %s want:%s %s", test.first, test.last, first, last, test.wantFirst, test.wantLast)
+		}
+		for _, w := range []string{first, last} {
+			if g.NodeFor(w) == nil {
+				t.Errorf("missing ladder word %q for %s to %s", w, test.first, test.last)
+			}
+		}
+		if g.NodeFor("worm") == nil {
+			t.Errorf("missing dictionary word for %s to %s", test.first, test.last)
+		}
+		if got := report.String(); !strings.Contains(got, test.wantWarning) || (test.wantWarning == "" && strings.Contains(got, "warning")) {
+			t.Errorf("unexpected report for %s to %s:\ngot:\n%s\nwant warning:\n%s", test.first, test.last, got, test.wantWarning)
+		}
+	}
+}
+
+func TestGraphSourceValid(t *testing.T) {
+	for _, test := range []struct {
+		src  GraphSource
+		want bool
+	}{
+		{src: GraphSource{}, want: true},
+		{src: GraphSource{Alphabet: "abc"}, want: true},
+		{src: GraphSource{Graph: "words.gv"}, want: true},
+		{src: GraphSource{Cache: "words.cache"}, want: true},
+		{src: GraphSource{Cache: "words.cache", Source: Source{Dictionaries: Dictionaries{"words.txt"}}}, want: true},
+		{src: GraphSource{Graph: "words.gv", Cache: "words.cache"}, want: false},
+		{src: GraphSource{Cache: "words.cache", Alphabet: "abc"}, want: false},
+		{src: GraphSource{Graph: "words.gv", Alphabet: "abc"}, want: false},
+		{src: GraphSource{Graph: "words.gv", Source: Source{ProperNouns: true}}, want: false},
+	} {
+		if got := test.src.Valid(); got != test.want {
+			t.Errorf("unexpected validity for %+v: got:%t want:%t", test.src, got, test.want)
+		}
+	}
+}
+
+func TestGraphSource(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"words.txt":  string(testDictionary()),
+		"ladder.gv":  "graph { cold -- cord -- word -- worm -- warm }",
+		"ladder.txt": "cold cord\ncord card\ncard ward\nward warm\n",
+	}
+	for name, text := range files {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0o664)
+		if err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	dict := Source{Dictionaries: Dictionaries{filepath.Join(dir, "words.txt")}}
+
+	for _, test := range []struct {
+		name string
+		src  GraphSource
+
+		first, last string
+		wantNodes   int
+		wantErr     string
+	}{
+		{
+			name:  "dictionary",
+			src:   GraphSource{Source: dict},
+			first: "COLD", last: "warm",
+			wantNodes: 27,
+		},
+		{
+			name:  "dictionary with missing word",
+			src:   GraphSource{Source: dict},
+			first: "cold", last: "wqrm",
+			wantNodes: 28,
+		},
+		{
+			name:  "cache",
+			src:   GraphSource{Source: dict, Cache: filepath.Join(dir, "words.cache")},
+			first: "COLD", last: "warm",
+			wantNodes: 27,
+		},
+		{
+			name:  "cache with missing word",
+			src:   GraphSource{Source: dict, Cache: filepath.Join(dir, "words.cache")},
+			first: "cold", last: "wqrm",
+			wantErr: `word not in dictionary: "wqrm" (did you mean "warm", "worm", "ward", "word", "wore"?)`,
+		},
+		{
+			name:  "DOT",
+			src:   GraphSource{Graph: filepath.Join(dir, "ladder.gv")},
+			first: "cold", last: "warm",
+			wantNodes: 5,
+		},
+		{
+			name:  "edge list",
+			src:   GraphSource{Graph: filepath.Join(dir, "ladder.txt")},
+			first: "cold", last: "warm",
+			wantNodes: 5,
+		},
+		{
+			name:  "edge list with missing node",
+			src:   GraphSource{Graph: filepath.Join(dir, "ladder.txt")},
+			first: "cold", last: "worm",
+			wantErr: `node must be in the graph: "worm"`,
+		},
+		{
+			name:  "missing graph",
+			src:   GraphSource{Graph: filepath.Join(dir, "missing.txt")},
+			first: "cold", last: "warm",
+			wantErr: "failed to read graph: ",
+		},
+	} {
+		g, first, last, err := test.src.LoadLadder(test.first, test.last, false, &strings.Builder{})
+		if test.wantErr != "" {
+			if err == nil || !strings.HasPrefix(err.Error(), test.wantErr) {
+				t.Errorf("unexpected error for %s:\ngot: %v\nwant:%s", test.name, err, test.wantErr)
+			}
+			test.src.Close()
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", test.name, err)
+			test.src.Close()
+			continue
+		}
+		if first != strings.ToLower(test.first) || last != strings.ToLower(test.last) {
+			t.Errorf("unexpected ladder words for %s: got:%s %s", test.name, first, last)
+		}
+		if n := len(graph.NodesOf(g.Nodes())); n != test.wantNodes {
+			t.Errorf("unexpected number of nodes for %s: got:%d want:%d", test.name, n, test.wantNodes)
+		}
+		if g.NodeFor(first) == nil || g.NodeFor(last) == nil {
+			t.Errorf("missing ladder words for %s", test.name)
+		}
+
+		err = test.src.Close()
+		if err != nil {
+			t.Errorf("unexpected error closing %s: %v", test.name, err)
+		}
+		g, err = test.src.Load(4, &strings.Builder{})
+		if err != nil {
+			t.Errorf("unexpected error loading graph for %s: %v", test.name, err)
+		} else if g.NodeFor(first) == nil {
+			t.Errorf("missing first word in loaded graph for %s", test.name)
+		}
+		err = test.src.Close()
+		if err != nil {
+			t.Errorf("unexpected error closing %s: %v", test.name, err)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package wordgraph
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/iterator"
+)
+
+// LazyGraph is a graph of distance-1 word paths using lazy implicit
+// edge calculation.
+type LazyGraph struct {
+	n     int
+	edit  bool
+	alpha alphabet
+	index
+	weights
+
+	// slice specifies that From copies all
+	// neighbours into a slice for iteration.
+	slice bool
+}
+
+// newLazyGraph returns a new LazyGraph for words of n letters.
+func newLazyGraph(n int, slice bool, cfg config) *LazyGraph {
+	return &LazyGraph{
+		n:       n,
+		edit:    cfg.edit,
+		alpha:   newAlphabet(cfg.letters),
+		index:   newIndex(),
+		weights: newWeights(cfg),
+		slice:   slice,
+	}
+}
+
+// Include adds word to the graph. Its distance-1 neighbours are
+// found when the graph is queried.
+func (g *LazyGraph) Include(word string) {
+	word = strings.ToLower(word)
+	if (g.n != 0 && utf8.RuneCountInString(word) != g.n) || !IsWord(word) {
+		return
+	}
+	if _, exists := g.ids[word]; exists {
+		return
+	}
+	if !g.alpha.admit(word) {
+		return
+	}
+	g.add(word)
+}
+
+// NodeFor returns a graph.Node representing the word for inclusion in a
+// LazyGraph.
+func (g LazyGraph) NodeFor(word string) graph.Node {
+	id, ok := g.ids[word]
+	if !ok {
+		return nil
+	}
+	return node{word: word, id: id}
+}
+
+// From implements the graph.Graph From method.
+func (g LazyGraph) From(id int64) graph.Nodes {
+	if uint64(id) >= uint64(len(g.words)) {
+		return graph.Empty
+	}
+	it := newNeighbours(g.index, id, g.edit)
+	if g.slice {
+		return iterator.NewOrderedNodes(graph.NodesOf(it))
+	}
+	return it
+}
+
+// Edge implements the graph.Graph Edge method.
+func (g LazyGraph) Edge(uid, vid int64) graph.Edge {
+	if !g.HasEdgeBetween(uid, vid) {
+		return nil
+	}
+	return edge{f: node{word: g.words[uid], id: uid}, t: node{word: g.words[vid], id: vid}}
+}
+
+// EdgeBetween implements the graph.Undirected EdgeBetween method.
+func (g LazyGraph) EdgeBetween(uid, vid int64) graph.Edge {
+	return g.Edge(uid, vid)
+}
+
+// HasEdgeBetween implements the graph.Graph HasEdgeBetween method.
+func (g LazyGraph) HasEdgeBetween(uid, vid int64) bool {
+	if uid == vid {
+		return false
+	}
+	if g.Node(uid) == nil || g.Node(vid) == nil {
+		return false
+	}
+	u := g.words[uid]
+	v := g.words[vid]
+	if g.edit {
+		return isEdit(u, v)
+	}
+	return utf8.RuneCountInString(u) == utf8.RuneCountInString(v) && HammingDistance(u, v) == 1
+}
+
+// HeuristicCost implements the path.HeuristicCoster interface. It returns
+// the Hamming distance between the words of x and y, or for Levenshtein
+// graphs, the Levenshtein distance, scaled by the minimum edit cost. Both
+// are admissible heuristics since each step of a ladder has a weight of
+// at least the minimum edit cost.
+func (g LazyGraph) HeuristicCost(x, y graph.Node) float64 {
+	return g.weights.heuristic(g.heuristicCost(x, y, g.edit))
+}
+
+// Node implements the graph.Graph Node method.
+func (g LazyGraph) Node(id int64) graph.Node {
+	if uint64(id) >= uint64(len(g.words)) {
+		return nil
+	}
+	return node{word: g.words[id], id: id}
+}
+
+// Nodes implements the graph.Graph Nodes method.
+func (g LazyGraph) Nodes() graph.Nodes {
+	if len(g.words) == 0 {
+		return graph.Empty
+	}
+	nodes := make([]graph.Node, len(g.words))
+	for id, w := range g.words {
+		nodes[id] = node{word: w, id: int64(id)}
+	}
+	return iterator.NewOrderedNodes(nodes)
+}
+
+// Weight implements the graph.Weighted Weight method. Edges have unit
+// weight unless the graph was constructed with the Familiarity or
+// EditCosts options.
+func (g LazyGraph) Weight(xid, yid int64) (w float64, ok bool) {
+	if xid == yid {
+		return 0, true
+	}
+	if !g.HasEdgeBetween(xid, yid) {
+		return math.Inf(1), false
+	}
+	return g.weights.between(g.words[xid], g.words[yid]), true
+}
+
+// WeightedEdge implements the graph.Weighted WeightedEdge method.
+func (g LazyGraph) WeightedEdge(uid, vid int64) graph.WeightedEdge {
+	w, ok := g.Weight(uid, vid)
+	if !ok || uid == vid {
+		return nil
+	}
+	return weightedEdge{edge: edge{f: node{word: g.words[uid], id: uid}, t: node{word: g.words[vid], id: vid}}, w: w}
+}
+
+// WeightedEdgeBetween implements the graph.WeightedUndirected
+// WeightedEdgeBetween method.
+func (g LazyGraph) WeightedEdgeBetween(xid, yid int64) graph.WeightedEdge {
+	return g.WeightedEdge(xid, yid)
+}
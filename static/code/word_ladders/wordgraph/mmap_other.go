@@ -0,0 +1,16 @@
+//go:build !(aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris)
+
+package wordgraph
+
+import "os"
+
+// mmap returns the contents of the named file. Memory mapping is not
+// supported on this platform, so the file is read into memory.
+func mmap(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+// munmap releases data returned by mmap.
+func munmap(data []byte) error {
+	return nil
+}
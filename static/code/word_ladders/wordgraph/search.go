@@ -0,0 +1,176 @@
+package wordgraph
+
+import (
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/traverse"
+)
+
+// Reachable returns the number of nodes in g that are reachable from u,
+// including u. This is the number of nodes expanded by path.DijkstraFrom
+// when searching from u.
+func Reachable(g traverse.Graph, u graph.Node) int {
+	var n int
+	bf := traverse.BreadthFirst{Visit: func(graph.Node) { n++ }}
+	bf.Walk(g, u, nil)
+	return n
+}
+
+// BidirectionalBetween returns a shortest path from s to t in the undirected
+// graph g. The path is found by breadth-first searches from both s and t that
+// meet in the middle. The number of nodes expanded by the searches is also
+// returned. If t is not reachable from s, path is nil.
+func BidirectionalBetween(g traverse.Graph, s, t graph.Node) (path []graph.Node, expanded int) {
+	meet, fwd, rev, expanded := bidirectional(g, s, t, false)
+	if meet == nil {
+		return nil, expanded
+	}
+	return join(fwd.pathTo(meet[0]), rev.pathTo(meet[0])), expanded
+}
+
+// BidirectionalAllBetween returns all shortest paths from s to t in the
+// undirected graph g. The paths are found by breadth-first searches from
+// both s and t that meet in the middle. The number of nodes expanded by
+// the searches is also returned. If t is not reachable from s, paths is
+// nil.
+func BidirectionalAllBetween(g traverse.Graph, s, t graph.Node) (paths [][]graph.Node, expanded int) {
+	meet, fwd, rev, expanded := bidirectional(g, s, t, true)
+	for _, m := range meet {
+		for _, p := range fwd.allPathsTo(m) {
+			for _, q := range rev.allPathsTo(m) {
+				paths = append(paths, join(p, q))
+			}
+		}
+	}
+	return paths, expanded
+}
+
+// bidirectional performs a pair of breadth-first searches from s and from t
+// that alternately expand the smaller of their frontiers a layer at a time.
+// It returns the nodes where the searches meet, and the searches from s and
+// from t. Every shortest path from s to t passes through exactly one of the
+// meeting nodes. If all is false, only the first meeting node found is
+// returned, and only the first parent of each node is recorded.
+func bidirectional(g traverse.Graph, s, t graph.Node, all bool) (meet []graph.Node, fwd, rev *bfs, expanded int) {
+	fwd = newBFS(s)
+	rev = newBFS(t)
+	if s.ID() == t.ID() {
+		return []graph.Node{s}, fwd, rev, 0
+	}
+	for len(fwd.frontier) != 0 && len(rev.frontier) != 0 {
+		near, far := fwd, rev
+		if len(rev.frontier) < len(fwd.frontier) {
+			near, far = rev, fwd
+		}
+		var n int
+		meet, n = near.expand(g, far, all)
+		expanded += n
+		near.frontier = near.next
+		near.next = nil
+		if meet != nil {
+			return meet, fwd, rev, expanded
+		}
+	}
+	return nil, fwd, rev, expanded
+}
+
+// bfs is the state of a layered breadth-first search.
+type bfs struct {
+	// depth and parents hold the depth of each
+	// visited node and the nodes in the previous
+	// layer that it is adjacent to.
+	depth   map[int64]int
+	parents map[int64][]graph.Node
+
+	frontier []graph.Node
+	next     []graph.Node
+}
+
+// newBFS returns a new breadth-first search rooted at n.
+func newBFS(n graph.Node) *bfs {
+	return &bfs{
+		depth:    map[int64]int{n.ID(): 0},
+		parents:  make(map[int64][]graph.Node),
+		frontier: []graph.Node{n},
+	}
+}
+
+// expand visits the nodes adjacent to the frontier of the search, adding
+// newly visited nodes to the next layer. It returns the newly visited nodes
+// that have been visited by the other search, and the number of frontier
+// nodes that were expanded. If all is false, expand returns on finding the
+// first such node.
+func (b *bfs) expand(g traverse.Graph, other *bfs, all bool) (meet []graph.Node, expanded int) {
+	for _, u := range b.frontier {
+		expanded++
+		d := b.depth[u.ID()] + 1
+		to := g.From(u.ID())
+		for to.Next() {
+			v := to.Node()
+			vid := v.ID()
+			dv, seen := b.depth[vid]
+			switch {
+			case !seen:
+				b.depth[vid] = d
+				b.parents[vid] = []graph.Node{u}
+				b.next = append(b.next, v)
+				if _, ok := other.depth[vid]; ok {
+					meet = append(meet, v)
+					if !all {
+						return meet, expanded
+					}
+				}
+			case dv == d && all:
+				b.parents[vid] = append(b.parents[vid], u)
+			}
+		}
+	}
+	return meet, expanded
+}
+
+// pathTo returns a path from the root of the search to n following the
+// first parent of each node.
+func (b *bfs) pathTo(n graph.Node) []graph.Node {
+	path := []graph.Node{n}
+	for p := b.parents[n.ID()]; len(p) != 0; p = b.parents[p[0].ID()] {
+		path = append(path, p[0])
+	}
+	reverse(path)
+	return path
+}
+
+// allPathsTo returns all the shortest paths from the root of the search
+// to n.
+func (b *bfs) allPathsTo(n graph.Node) [][]graph.Node {
+	parents := b.parents[n.ID()]
+	if len(parents) == 0 {
+		return [][]graph.Node{{n}}
+	}
+	var paths [][]graph.Node
+	for _, u := range parents {
+		for _, p := range b.allPathsTo(u) {
+			path := make([]graph.Node, len(p)+1)
+			copy(path, p)
+			path[len(p)] = n
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// join returns the path from s to t through m given a path, p, from s to m
+// and a path, q, from t to m.
+func join(p, q []graph.Node) []graph.Node {
+	path := make([]graph.Node, len(p), len(p)+len(q)-1)
+	copy(path, p)
+	for i := len(q) - 2; i >= 0; i-- {
+		path = append(path, q[i])
+	}
+	return path
+}
+
+// reverse reverses the order of nodes.
+func reverse(nodes []graph.Node) {
+	for i, j := 0, len(nodes)-1; i < j; i, j = i+1, j-1 {
+		nodes[i], nodes[j] = nodes[j], nodes[i]
+	}
+}
@@ -0,0 +1,88 @@
+package wordgraph
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/iterator"
+	"gonum.org/v1/gonum/graph/path"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestBidirectional(t *testing.T) {
+	for _, test := range conformanceTests {
+		g := New(test.kind, test.n, test.opts...)
+		for _, w := range testWords {
+			g.Include(w)
+		}
+		nodes := graph.NodesOf(g.Nodes())
+		for _, s := range nodes {
+			pth := path.DijkstraAllFrom(s, g)
+			for _, u := range nodes {
+				want, _ := pth.AllTo(u.ID())
+
+				got, _ := BidirectionalAllBetween(g, s, u)
+				if !reflect.DeepEqual(ladderWords(got), ladderWords(want)) {
+					t.Errorf("unexpected ladders from %v to %v in %s graph:\ngot: %v\nwant:%v", s, u, test.name, got, want)
+				}
+
+				ladder, _ := BidirectionalBetween(g, s, u)
+				switch {
+				case len(want) == 0:
+					if ladder != nil {
+						t.Errorf("unexpected ladder from %v to %v in %s graph: %v", s, u, test.name, ladder)
+					}
+				case !contains(ladderWords(want), ladderWords([][]graph.Node{ladder})[0]):
+					t.Errorf("unexpected ladder from %v to %v in %s graph:\ngot: %v\nwant one of:%v", s, u, test.name, ladder, want)
+				}
+			}
+		}
+	}
+}
+
+func TestBidirectionalExpanded(t *testing.T) {
+	// The search from 0 meets the search from 9
+	// on expanding 1, the first node of its second
+	// layer. Searching for all ladders expands the
+	// rest of that layer to find the ladder via 3.
+	g := orderedGraph{simple.NewUndirectedGraph()}
+	for _, e := range [][2]int64{
+		{0, 1}, {0, 2}, {0, 3},
+		{9, 4}, {9, 5}, {9, 6}, {9, 7},
+		{1, 4}, {3, 7},
+	} {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+	}
+
+	ladder, expanded := BidirectionalBetween(g, simple.Node(0), simple.Node(9))
+	if want := "[0 1 4 9]"; fmt.Sprint(ladder) != want {
+		t.Errorf("unexpected ladder: got:%v want:%s", ladder, want)
+	}
+	if expanded != 3 {
+		t.Errorf("unexpected number of nodes expanded for one ladder: got:%d want:3", expanded)
+	}
+
+	ladders, expanded := BidirectionalAllBetween(g, simple.Node(0), simple.Node(9))
+	if want := "[[0 1 4 9] [0 3 7 9]]"; fmt.Sprint(ladders) != want {
+		t.Errorf("unexpected ladders: got:%v want:%s", ladders, want)
+	}
+	if expanded != 5 {
+		t.Errorf("unexpected number of nodes expanded for all ladders: got:%d want:5", expanded)
+	}
+}
+
+// orderedGraph is an undirected graph that returns
+// the nodes adjacent to a node in order of their IDs.
+type orderedGraph struct {
+	*simple.UndirectedGraph
+}
+
+// From implements the graph.Graph From method.
+func (g orderedGraph) From(id int64) graph.Nodes {
+	nodes := graph.NodesOf(g.UndirectedGraph.From(id))
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID() < nodes[j].ID() })
+	return iterator.NewOrderedNodes(nodes)
+}
@@ -0,0 +1,204 @@
+package wordgraph
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// MaxSuggestions is the maximum number of words suggested by Check
+// for each word that is not in the dictionary.
+const MaxSuggestions = 5
+
+// Suggest returns up to n words in g within Levenshtein distance two of
+// word, other than word, ordered by distance and then alphabetically.
+// The words of an EagerGraph or LazyGraph are found using its neighbour
+// index, otherwise all the words in g are compared with word.
+func Suggest(g Graph, word string, n int) []string {
+	word = strings.ToLower(word)
+	var words []string
+	if idx, ok := g.(interface{ suggest(string) []string }); ok {
+		words = idx.suggest(word)
+	} else {
+		for it := g.Nodes(); it.Next(); {
+			w := fmt.Sprint(it.Node())
+			if w != word && EditDistance(w, word) <= 2 {
+				words = append(words, w)
+			}
+		}
+	}
+
+	dist := make(map[string]int, len(words))
+	for _, w := range words {
+		dist[w] = EditDistance(w, word)
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if dist[words[i]] != dist[words[j]] {
+			return dist[words[i]] < dist[words[j]]
+		}
+		return words[i] < words[j]
+	})
+	if len(words) > n {
+		words = words[:n]
+	}
+	return words
+}
+
+// suggest returns the words in the index within Levenshtein distance two
+// of word, other than word, in no particular order. Words one edit away
+// are found in the index directly, and words two edits away are found
+// from each word one edit away, whether or not it is in the index, using
+// the letters of the words in the index.
+func (idx index) suggest(word string) []string {
+	found := make(map[string]bool)
+	search := func(w []rune) {
+		it := neighbours{idx: idx, id: -1, word: w, edit: true}
+		for it.Next() {
+			found[it.curr.(node).word] = true
+		}
+	}
+	r := []rune(word)
+	search(r)
+	for _, w := range edits(r, Letters(idx.ids)) {
+		search(w)
+	}
+	delete(found, word)
+
+	var words []string
+	for w := range found {
+		words = append(words, w)
+	}
+	return words
+}
+
+// edits returns the words a single substitution, deletion or insertion
+// of a letter from word.
+func edits(word, letters []rune) [][]rune {
+	var words [][]rune
+	for j := range word {
+		for _, c := range letters {
+			if c == word[j] {
+				continue
+			}
+			w := append([]rune(nil), word...)
+			w[j] = c
+			words = append(words, w)
+		}
+		words = append(words, append(append([]rune(nil), word[:j]...), word[j+1:]...))
+	}
+	for j := 0; j <= len(word); j++ {
+		for _, c := range letters {
+			w := append(append(append([]rune(nil), word[:j]...), c), word[j:]...)
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// NotInDictionary is the error for a ladder word that is not in the
+// dictionary.
+type NotInDictionary struct {
+	Word string
+
+	// Suggestions holds dictionary
+	// words similar to Word.
+	Suggestions []string
+}
+
+// Error returns a description of the missing word and the suggested
+// alternatives.
+func (e *NotInDictionary) Error() string {
+	if len(e.Suggestions) == 0 {
+		return fmt.Sprintf("word not in dictionary: %q", e.Word)
+	}
+	quoted := make([]string, len(e.Suggestions))
+	for i, w := range e.Suggestions {
+		quoted[i] = fmt.Sprintf("%q", w)
+	}
+	return fmt.Sprintf("word not in dictionary: %q (did you mean %s?)", e.Word, strings.Join(quoted, ", "))
+}
+
+// Endpoints records whether the first and last words of a ladder, which
+// are included in a graph before the dictionary is read so that ladders
+// can be found between any words, are read from the dictionary.
+type Endpoints struct {
+	words []string
+	found map[string]bool
+}
+
+// NewEndpoints returns a new Endpoints for the given lower case words.
+func NewEndpoints(words ...string) *Endpoints {
+	return &Endpoints{words: words, found: make(map[string]bool)}
+}
+
+// Wrap returns a function for ReadDictionaries that passes words to
+// include and records the endpoints that include accepts or has already
+// accepted.
+func (e *Endpoints) Wrap(include func(word string) Status) func(word string) Status {
+	return func(word string) Status {
+		s := include(word)
+		if s == Accepted || s == Duplicate {
+			e.found[strings.ToLower(word)] = true
+		}
+		return s
+	}
+}
+
+// Check returns a NotInDictionary error for each endpoint that was not
+// read from the dictionary, suggesting words of g that were.
+func (e *Endpoints) Check(g Graph) []error {
+	var errs []error
+	for i, w := range e.words {
+		if e.found[w] || contains(e.words[:i], w) {
+			continue
+		}
+		var suggestions []string
+		for _, s := range Suggest(g, w, MaxSuggestions+len(e.words)) {
+			if !contains(e.words, s) || e.found[s] {
+				suggestions = append(suggestions, s)
+			}
+		}
+		if len(suggestions) > MaxSuggestions {
+			suggestions = suggestions[:MaxSuggestions]
+		}
+		errs = append(errs, &NotInDictionary{Word: w, Suggestions: suggestions})
+	}
+	return errs
+}
+
+// Found returns whether all the endpoints were read from the dictionary.
+func (e *Endpoints) Found() bool {
+	for _, w := range e.words {
+		if !e.found[w] {
+			return false
+		}
+	}
+	return true
+}
+
+// Report writes the errors returned by Check to w, and returns whether
+// the ladder should be rejected. In strict mode the errors are written
+// as they are and the ladder is rejected if there are any, otherwise
+// they are written as warnings.
+func (e *Endpoints) Report(w io.Writer, g Graph, strict bool) (reject bool) {
+	missing := e.Check(g)
+	for _, err := range missing {
+		if strict {
+			fmt.Fprintln(w, err)
+		} else {
+			fmt.Fprintf(w, "warning: %v\n", err)
+		}
+	}
+	return strict && len(missing) != 0
+}
+
+// contains returns whether word is in words.
+func contains(words []string, word string) bool {
+	for _, w := range words {
+		if w == word {
+			return true
+		}
+	}
+	return false
+}
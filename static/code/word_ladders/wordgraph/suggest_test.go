@@ -0,0 +1,137 @@
+package wordgraph
+
+import (
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestSuggest(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "words.cache")
+	c, err := LoadCache(name, testDictionary())
+	if err != nil {
+		t.Fatalf("failed to load cache: %v", err)
+	}
+	defer c.Close()
+
+	for _, n := range []int{0, 4} {
+		graphs := map[string]Graph{
+			"eager": New(Eager, n),
+			"lazy":  New(Lazy, n, Levenshtein()),
+		}
+		for _, g := range graphs {
+			for _, w := range testWords {
+				g.Include(w)
+			}
+		}
+		if n != 0 {
+			graphs["cached"] = c.Graph(n)
+		}
+
+		for _, word := range []string{"cold", "wrod", "wqrm", "cafe", "zzzz", "boats"} {
+			// Find the suggestions by comparing
+			// every word with the query word.
+			var want []string
+			for _, w := range testWords {
+				if w != word && EditDistance(w, word) <= 2 && (n == 0 || len([]rune(w)) == n) {
+					want = append(want, w)
+				}
+			}
+			sort.Slice(want, func(i, j int) bool {
+				di, dj := EditDistance(want[i], word), EditDistance(want[j], word)
+				if di != dj {
+					return di < dj
+				}
+				return want[i] < want[j]
+			})
+
+			for kind, g := range graphs {
+				got := Suggest(g, word, len(testWords))
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("unexpected suggestions for %q from %s graph of length %d:\ngot: %q\nwant:%q", word, kind, n, got, want)
+				}
+				if len(want) > 2 {
+					if got := Suggest(g, word, 2); !reflect.DeepEqual(got, want[:2]) {
+						t.Errorf("unexpected limited suggestions for %q from %s graph of length %d:\ngot: %q\nwant:%q", word, kind, n, got, want[:2])
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestEndpoints(t *testing.T) {
+	g := New(Lazy, 4)
+	ends := NewEndpoints("wrod", "cold", "word")
+	for _, w := range ends.words {
+		g.Include(w)
+	}
+	include := ends.Wrap(Includer(g))
+	for _, w := range []string{"Cold", "cord", "card", "ward", "warm", "worm", "cold"} {
+		include(w)
+	}
+
+	got := ends.Check(g)
+	want := []error{
+		&NotInDictionary{Word: "wrod", Suggestions: []string{"ward"}},
+		&NotInDictionary{Word: "word", Suggestions: []string{"cord", "ward", "worm", "card", "cold"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected errors:\ngot: %v\nwant:%v", got, want)
+	}
+	wantMsg := `word not in dictionary: "wrod" (did you mean "ward"?)`
+	if msg := got[0].Error(); msg != wantMsg {
+		t.Errorf("unexpected error message:\ngot: %s\nwant:%s", msg, wantMsg)
+	}
+	if msg := (&NotInDictionary{Word: "zzzz"}).Error(); msg != `word not in dictionary: "zzzz"` {
+		t.Errorf("unexpected error message without suggestions: %s", msg)
+	}
+	if ends.Found() {
+		t.Error("unexpected found endpoints with missing words")
+	}
+
+	for _, test := range []struct {
+		strict bool
+		reject bool
+		want   string
+	}{
+		{
+			strict: false,
+			reject: false,
+			want: `warning: word not in dictionary: "wrod" (did you mean "ward"?)
+warning: word not in dictionary: "word" (did you mean "cord", "ward", "worm", "card", "cold"?)
+`,
+		},
+		{
+			strict: true,
+			reject: true,
+			want: `word not in dictionary: "wrod" (did you mean "ward"?)
+word not in dictionary: "word" (did you mean "cord", "ward", "worm", "card", "cold"?)
+`,
+		},
+	} {
+		var buf strings.Builder
+		reject := ends.Report(&buf, g, test.strict)
+		if reject != test.reject {
+			t.Errorf("unexpected rejection with strict=%t: got:%t want:%t", test.strict, reject, test.reject)
+		}
+		if buf.String() != test.want {
+			t.Errorf("unexpected report with strict=%t:\ngot:\n%s\nwant:\n%s", test.strict, buf.String(), test.want)
+		}
+	}
+
+	found := NewEndpoints("cold", "warm")
+	include = found.Wrap(Includer(g))
+	for _, w := range []string{"cold", "warm"} {
+		include(w)
+	}
+	if !found.Found() {
+		t.Error("expected found endpoints")
+	}
+	var buf strings.Builder
+	if found.Report(&buf, g, true) || buf.Len() != 0 {
+		t.Errorf("unexpected report for found endpoints: %q", buf.String())
+	}
+}
@@ -0,0 +1,122 @@
+package wordgraph
+
+import (
+	"fmt"
+	"strings"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/traverse"
+)
+
+// Component is a connected component of a word graph.
+type Component struct {
+	// Name is the alphabetically
+	// first word in the component.
+	Name string
+
+	// Size is the number of words
+	// in the component.
+	Size int
+}
+
+// String returns a description of the component.
+func (c Component) String() string {
+	if c.Size == 1 {
+		return fmt.Sprintf("component %q of 1 word", c.Name)
+	}
+	return fmt.Sprintf("component %q of %d words", c.Name, c.Size)
+}
+
+// NoLadder is the error returned when there is no ladder between two
+// words. It describes the components holding the words, and the word
+// reachable from First that is nearest to Last.
+type NoLadder struct {
+	First, Last string
+
+	FirstComponent Component
+	LastComponent  Component
+
+	// Nearest is the word reachable from First
+	// with the least distance to Last, and Ladder
+	// is a shortest ladder from First to Nearest.
+	Nearest  string
+	Distance int
+	Ladder   []string
+}
+
+// Error returns a multi-line description of the missing ladder.
+func (e *NoLadder) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "no ladder from %q to %q\n", e.First, e.Last)
+	fmt.Fprintf(&b, "\t%q is in %v\n", e.First, e.FirstComponent)
+	fmt.Fprintf(&b, "\t%q is in %v\n", e.Last, e.LastComponent)
+	fmt.Fprintf(&b, "\tnearest word to %q reachable from %q is %q at distance %d:\n", e.Last, e.First, e.Nearest, e.Distance)
+	fmt.Fprintf(&b, "\t%v", e.Ladder)
+	return b.String()
+}
+
+// Unreachable returns a NoLadder describing the components of g holding
+// first and last, which must not be connected. The words of nodes are
+// given by word, or by formatting the nodes with fmt.Sprint, as for the
+// nodes of a Graph, if word is nil. The word nearest to last is chosen
+// by the given distance function. Ties are broken by the number of steps
+// from first and then alphabetically.
+func Unreachable(g traverse.Graph, first, last graph.Node, word func(graph.Node) string, distance func(a, b string) int) *NoLadder {
+	if word == nil {
+		word = func(n graph.Node) string { return fmt.Sprint(n) }
+	}
+	target := word(last)
+	e := &NoLadder{
+		First: word(first),
+		Last:  target,
+	}
+
+	// Walk the component holding first, keeping
+	// the path to each word, to find the nearest.
+	parent := map[int64]graph.Node{first.ID(): nil}
+	depth := map[int64]int{first.ID(): 0}
+	var nearest graph.Node
+	bf := traverse.BreadthFirst{
+		Traverse: func(edge graph.Edge) bool {
+			if _, seen := parent[edge.To().ID()]; !seen {
+				parent[edge.To().ID()] = edge.From()
+				depth[edge.To().ID()] = depth[edge.From().ID()] + 1
+			}
+			return true
+		},
+		Visit: func(u graph.Node) {
+			w := word(u)
+			e.FirstComponent.add(w)
+			d := distance(w, target)
+			if nearest == nil || d < e.Distance ||
+				(d == e.Distance && depth[u.ID()] < depth[nearest.ID()]) ||
+				(d == e.Distance && depth[u.ID()] == depth[nearest.ID()] && w < e.Nearest) {
+				nearest = u
+				e.Nearest = w
+				e.Distance = d
+			}
+		},
+	}
+	bf.Walk(g, first, nil)
+	for u := nearest; u != nil; u = parent[u.ID()] {
+		e.Ladder = append(e.Ladder, word(u))
+	}
+	for i, j := 0, len(e.Ladder)-1; i < j; i, j = i+1, j-1 {
+		e.Ladder[i], e.Ladder[j] = e.Ladder[j], e.Ladder[i]
+	}
+
+	bf = traverse.BreadthFirst{Visit: func(u graph.Node) {
+		e.LastComponent.add(word(u))
+	}}
+	bf.Walk(g, last, nil)
+
+	return e
+}
+
+// add adds word to the component.
+func (c *Component) add(word string) {
+	if c.Size == 0 || word < c.Name {
+		c.Name = word
+	}
+	c.Size++
+}
@@ -0,0 +1,29 @@
+package wordgraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnreachable(t *testing.T) {
+	for _, kind := range []Kind{Eager, Lazy} {
+		g := New(kind, 4)
+		for _, w := range []string{"cold", "cord", "card", "ward", "warm", "word", "mind", "wind", "wine"} {
+			g.Include(w)
+		}
+
+		got := Unreachable(g, g.NodeFor("cold"), g.NodeFor("wine"), nil, HammingDistance)
+		want := &NoLadder{
+			First:          "cold",
+			Last:           "wine",
+			FirstComponent: Component{Name: "card", Size: 6},
+			LastComponent:  Component{Name: "mind", Size: 3},
+			Nearest:        "word",
+			Distance:       3,
+			Ladder:         []string{"cold", "cord", "word"},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("unexpected result for kind %v:\ngot: %+v\nwant:%+v", kind, got, want)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package wordgraph
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"unicode"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// CountWords returns the number of times each word appears in the text
+// read from r. Words are runs of letters and are counted in lower case.
+func CountWords(r io.Reader) (map[string]int, error) {
+	counts := make(map[string]int)
+	sc := bufio.NewScanner(r)
+	sc.Split(bufio.ScanWords)
+	for sc.Scan() {
+		notLetter := func(c rune) bool { return !unicode.IsLetter(c) }
+		for _, w := range strings.FieldsFunc(sc.Text(), notLetter) {
+			counts[strings.ToLower(w)]++
+		}
+	}
+	return counts, sc.Err()
+}
+
+// ReadCorpus returns the number of times each word appears in the named
+// text file, which is opened as for OpenDictionary.
+func ReadCorpus(name string) (map[string]int, error) {
+	f, err := OpenDictionary(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	counts, err := CountWords(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return counts, nil
+}
+
+// Weighting is a weighting of the edges of a word graph for a command,
+// configured by command line flags. Word familiarity is counted in the
+// corpus of the command's Source.
+type Weighting struct {
+	Familiarity float64
+	Costs       string
+}
+
+// RegisterFlags registers the flags that configure the weighting in fs.
+func (w *Weighting) RegisterFlags(fs *flag.FlagSet) {
+	fs.Float64Var(&w.Familiarity, "familiarity", 1, "weight given to word familiarity relative to ladder length (requires -corpus)")
+	fs.StringVar(&w.Costs, "costs", "", `letter edit cost table file, or "keyboard" or "vowel" presets`)
+}
+
+// Options returns the options that apply the weighting to a word graph.
+// Ladders through words that are common in the corpus of src are preferred,
+// and letter edits are weighted by the cost table.
+func (w *Weighting) Options(src *Source) ([]Option, error) {
+	var opts []Option
+	if src.Corpus != "" {
+		counts, err := src.Counts()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, Familiarity(counts, w.Familiarity))
+	}
+	if w.Costs != "" {
+		c, err := LoadCosts(w.Costs)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, EditCosts(c))
+	}
+	return opts, nil
+}
+
+// weights holds the parameters used to calculate the weights of edges
+// in a word graph.
+type weights struct {
+	// counts is the number of times each
+	// word appears in a corpus and logMax
+	// is the log of one more than the count
+	// of the most common word.
+	counts map[string]int
+	logMax float64
+
+	// scale is the weight given to the
+	// obscurity of words joined by an edge.
+	scale float64
+
+	// costs holds the costs of letter edits
+	// and minCost is the minimum edit cost.
+	costs   *Costs
+	minCost float64
+}
+
+// newWeights returns the weights for a word graph constructed with the
+// given configuration.
+func newWeights(cfg config) weights {
+	w := weights{counts: cfg.counts, scale: cfg.scale, costs: cfg.costs, minCost: 1}
+	for _, n := range cfg.counts {
+		w.logMax = math.Max(w.logMax, math.Log1p(float64(n)))
+	}
+	if cfg.costs != nil {
+		w.minCost = cfg.costs.min()
+	}
+	return w
+}
+
+// between returns the weight of an edge joining the words u and v.
+func (w weights) between(u, v string) float64 {
+	weight := 1.0
+	if w.costs != nil {
+		weight = w.costs.between(u, v)
+	}
+	if w.scale != 0 {
+		weight += w.scale * (w.obscurity(u) + w.obscurity(v)) / 2
+	}
+	return weight
+}
+
+// heuristic returns an admissible heuristic cost for a path of at least
+// d edges.
+func (w weights) heuristic(d float64) float64 {
+	if w.minCost == 0 {
+		return 0
+	}
+	return d * w.minCost
+}
+
+// obscurity returns the obscurity of word, ranging from zero for the
+// most common word in the corpus to one for words that do not appear
+// in the corpus.
+func (w weights) obscurity(word string) float64 {
+	if w.logMax == 0 {
+		return 0
+	}
+	return 1 - math.Log1p(float64(w.counts[word]))/w.logMax
+}
+
+// weightedEdge is a weighted distance-1 relationship between words in a
+// word graph.
+type weightedEdge struct {
+	edge
+	w float64
+}
+
+func (e weightedEdge) ReversedEdge() graph.Edge {
+	return weightedEdge{e.edge.ReversedEdge().(edge), e.w}
+}
+
+func (e weightedEdge) Weight() float64 { return e.w }
@@ -0,0 +1,179 @@
+package wordgraph
+
+import (
+	"compress/gzip"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/path"
+)
+
+func TestCountWords(t *testing.T) {
+	text := "It's the cat's hat.\nThe CAT sat—on it!  Naïve café, naïve\tcafés."
+	got, err := CountWords(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]int{
+		"it": 2, "s": 2, "the": 2, "cat": 2, "hat": 1, "sat": 1, "on": 1,
+		"naïve": 2, "café": 1, "cafés": 1,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected counts:\ngot: %v\nwant:%v", got, want)
+	}
+}
+
+func TestReadCorpus(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "corpus.txt.gz")
+	f, err := os.Create(name)
+	if err != nil {
+		t.Fatalf("failed to create corpus: %v", err)
+	}
+	w := gzip.NewWriter(f)
+	w.Write([]byte("The cat sat on the mat."))
+	w.Close()
+	f.Close()
+
+	got, err := ReadCorpus(name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]int{"the": 2, "cat": 1, "sat": 1, "on": 1, "mat": 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected counts:\ngot: %v\nwant:%v", got, want)
+	}
+	_, err = ReadCorpus(filepath.Join(dir, "missing.txt"))
+	if err == nil {
+		t.Error("expected error for missing corpus")
+	}
+}
+
+func TestWeightingOptions(t *testing.T) {
+	dir := t.TempDir()
+	corpus := filepath.Join(dir, "corpus.txt")
+	err := os.WriteFile(corpus, []byte("cat cot cot"), 0o664)
+	if err != nil {
+		t.Fatalf("failed to write corpus: %v", err)
+	}
+	for _, test := range []struct {
+		name      string
+		src       Source
+		weighting Weighting
+		want      config
+		wantErr   bool
+	}{
+		{name: "none", weighting: Weighting{Familiarity: 1}, want: config{}},
+		{
+			name:      "corpus",
+			src:       Source{Corpus: corpus},
+			weighting: Weighting{Familiarity: 2},
+			want:      config{counts: map[string]int{"cat": 1, "cot": 2}, scale: 2},
+		},
+		{
+			name:      "costs",
+			weighting: Weighting{Familiarity: 1, Costs: "vowel"},
+			want:      config{costs: VowelCosts()},
+		},
+		{name: "missing corpus", src: Source{Corpus: filepath.Join(dir, "missing.txt")}, wantErr: true},
+		{name: "missing costs", weighting: Weighting{Costs: filepath.Join(dir, "missing.txt")}, wantErr: true},
+	} {
+		opts, err := test.weighting.Options(&test.src)
+		if (err != nil) != test.wantErr {
+			t.Errorf("unexpected error for %s: %v", test.name, err)
+			continue
+		}
+		var got config
+		for _, o := range opts {
+			o(&got)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("unexpected configuration for %s:\ngot: %+v\nwant:%+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestObscurity(t *testing.T) {
+	var cfg config
+	Familiarity(map[string]int{"the": 99, "cat": 9, "cot": 0}, 2)(&cfg)
+	w := newWeights(cfg)
+	for _, test := range []struct {
+		word string
+		want float64
+	}{
+		{word: "the", want: 0},
+		{word: "cat", want: 0.5},
+		{word: "cot", want: 1},
+		{word: "dot", want: 1},
+	} {
+		if got := w.obscurity(test.word); math.Abs(got-test.want) > 1e-12 {
+			t.Errorf("unexpected obscurity of %q: got:%v want:%v", test.word, got, test.want)
+		}
+	}
+
+	for _, test := range []struct {
+		u, v string
+		want float64
+	}{
+		{u: "the", v: "the", want: 1},
+		{u: "cat", v: "cot", want: 2.5},
+		{u: "cot", v: "cat", want: 2.5},
+		{u: "cot", v: "dot", want: 3},
+	} {
+		if got := w.between(test.u, test.v); math.Abs(got-test.want) > 1e-12 {
+			t.Errorf("unexpected weight between %q and %q: got:%v want:%v", test.u, test.v, got, test.want)
+		}
+	}
+
+	// Without a corpus or scale all edges have unit weight.
+	for _, cfg := range []config{{}, {counts: map[string]int{"cat": 10}}} {
+		w := newWeights(cfg)
+		if got := w.between("cat", "cot"); got != 1 {
+			t.Errorf("unexpected weight without familiarity: got:%v want:1", got)
+		}
+	}
+}
+
+func TestFamiliarLadder(t *testing.T) {
+	// There are two equal length ladders from cat to
+	// dot, one through cot and the other through dat.
+	words := []string{"cat", "cot", "dat", "dot"}
+	for _, test := range []struct {
+		counts map[string]int
+		want   string
+	}{
+		{counts: map[string]int{"cat": 5, "cot": 20, "dot": 5}, want: "cat cot dot"},
+		{counts: map[string]int{"cat": 5, "dat": 20, "dot": 5}, want: "cat dat dot"},
+	} {
+		for _, kind := range []Kind{Eager, Lazy} {
+			g := New(kind, 3, Familiarity(test.counts, 1))
+			for _, w := range words {
+				g.Include(w)
+			}
+			ladder, weight := path.DijkstraFrom(g.NodeFor("cat"), g).To(g.NodeFor("dot").ID())
+			if got := ladderWords([][]graph.Node{ladder})[0]; got != test.want {
+				t.Errorf("unexpected ladder for counts %v: got:%q want:%q", test.counts, got, test.want)
+			}
+			if weight <= 2 || weight >= 4 {
+				t.Errorf("unexpected ladder weight for counts %v: got:%v want:(2,4)", test.counts, weight)
+			}
+		}
+	}
+}
+
+func TestWeightedReversedEdge(t *testing.T) {
+	u := node{word: "cat", id: 0}
+	v := node{word: "cot", id: 1}
+	for _, ladder := range []bool{false, true} {
+		e := weightedEdge{edge{f: u, t: v, ladder: ladder}, 1.5}
+		want := weightedEdge{edge{f: v, t: u, ladder: ladder}, 1.5}
+		if got := e.ReversedEdge(); got != want {
+			t.Errorf("unexpected reversed edge: got:%+v want:%+v", got, want)
+		}
+	}
+}
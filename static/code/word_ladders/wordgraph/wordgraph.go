@@ -0,0 +1,312 @@
+// Package wordgraph provides graphs for finding word ladders between pairs
+// of words in a dictionary. Words are stored as nodes within the graph
+// and are joined by an edge when they are Hamming distance one apart,
+// or with the Levenshtein option, when they are Levenshtein distance
+// one apart.
+//
+// Two implementations are provided. The EagerGraph constructs all edges
+// between words on addition of the words to the graph. The LazyGraph
+// implies edges by word distance and enumerates them lazily when
+// neighbouring nodes are queried.
+package wordgraph // import "gonum.org/website/static/code/word_ladders/wordgraph"
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// Graph is a graph of distance-1 word paths. Once all words have been
+// included, a Graph is safe for concurrent queries. Include must not be
+// called concurrently with any other method.
+type Graph interface {
+	graph.Undirected
+	graph.WeightedUndirected
+
+	// Include adds word to the graph if it is a valid
+	// word with the length of words held by the graph.
+	// Graphs holding words of all lengths include any
+	// valid word.
+	Include(word string)
+
+	// NodeFor returns a graph.Node representing the word,
+	// or nil if the word is not in the graph.
+	NodeFor(word string) graph.Node
+}
+
+// Kind is a word graph implementation.
+type Kind int
+
+const (
+	// Eager is a graph that encapsulates a Gonum
+	// simple.UndirectedGraph and constructs all edges
+	// between words on addition of the words.
+	Eager Kind = iota
+
+	// Lazy is a graph with edges implied by word
+	// distance that are enumerated by an iterator when
+	// neighbouring nodes are queried.
+	Lazy
+
+	// LazySlice is a Lazy graph that copies all
+	// neighbours into a slice for iteration.
+	LazySlice
+)
+
+// New returns a new word graph of the given kind for words of n characters.
+// If n is zero, the graph holds words of all lengths.
+func New(kind Kind, n int, opts ...Option) Graph {
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	switch kind {
+	case Eager:
+		return newEagerGraph(n, cfg)
+	case Lazy:
+		return newLazyGraph(n, false, cfg)
+	case LazySlice:
+		return newLazyGraph(n, true, cfg)
+	default:
+		panic("wordgraph: unknown graph kind")
+	}
+}
+
+// Option is a word graph construction option.
+type Option func(*config)
+
+// config holds the options used to construct a word graph.
+type config struct {
+	edit    bool
+	letters string
+
+	counts map[string]int
+	scale  float64
+
+	costs *Costs
+}
+
+// Levenshtein specifies that words are joined when they are Levenshtein
+// distance one apart, so each step of a ladder may substitute, insert
+// or delete a letter. Used with a word length of zero, a single graph
+// spans all word lengths.
+func Levenshtein() Option {
+	return func(c *config) {
+		c.edit = true
+	}
+}
+
+// Familiarity specifies that edges are weighted to prefer ladders through
+// familiar words, with familiarity given by the number of times each word
+// appears in a corpus, as returned by CountWords. The obscurity of a word
+// ranges from zero for the most common word in the corpus to one for words
+// that do not appear in it, on a logarithmic scale of counts. An edge
+// joining words u and v has the weight
+//
+//	1 + scale×(obscurity(u)+obscurity(v))/2
+//
+// so scale trades ladder length off against word familiarity. Without a
+// Familiarity option, or with a zero scale, edges have unit weight unless
+// the EditCosts option is used.
+func Familiarity(counts map[string]int, scale float64) Option {
+	return func(c *config) {
+		c.counts = counts
+		c.scale = scale
+	}
+}
+
+// EditCosts specifies that edges are weighted by the cost of the single
+// letter edit that joins the words, as given by the costs table. When
+// used with the Familiarity option, the edit cost replaces the unit
+// length of each edge.
+func EditCosts(costs *Costs) Option {
+	return func(c *config) {
+		c.costs = costs
+	}
+}
+
+// Alphabet specifies the letters that may be used in words. Words
+// containing letters that are not in the alphabet are not included in
+// the graph. If letters is empty or no Alphabet option is given, the
+// alphabet is inferred from the words included in the graph.
+func Alphabet(letters string) Option {
+	return func(c *config) {
+		c.letters = letters
+	}
+}
+
+// IsWord returns whether s is a non-empty string of letters. Letters are
+// not restricted to ASCII, but must be precomposed; words holding combining
+// marks are not considered to be words.
+func IsWord(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if !unicode.IsLetter(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// Neighbours returns a slice of string of words in the words map
+// that are within Hamming distance one from the query word, using
+// the provided letters to construct candidate neighbours. This is the
+// naive lookup of each candidate in the words map used by words-0; the
+// word graphs find neighbours using an index of wildcard patterns
+// instead.
+func Neighbours(word string, words map[string]int64, letters []rune) []string {
+	var adj []string
+	r := []rune(word)
+	b := make([]rune, len(r))
+	for j := range r {
+		for _, d := range letters {
+			if d == r[j] {
+				continue
+			}
+			copy(b, r)
+			b[j] = d
+			w := string(b)
+			if _, ok := words[w]; ok {
+				// We have found a neighbouring word so we
+				// can add it to our list of neighbours.
+				adj = append(adj, w)
+			}
+		}
+	}
+	return adj
+}
+
+// EditNeighbours returns a slice of string of words in the words map
+// that are within Levenshtein distance one from the query word, using
+// the provided letters to construct candidate neighbours.
+func EditNeighbours(word string, words map[string]int64, letters []rune) []string {
+	adj := Neighbours(word, words, letters)
+	r := []rune(word)
+
+	// Deleting any letter from a run of repeated letters gives
+	// the same word, so only delete the first letter of a run.
+	for j := range r {
+		if j > 0 && r[j-1] == r[j] {
+			continue
+		}
+		w := string(r[:j]) + string(r[j+1:])
+		if _, ok := words[w]; ok {
+			adj = append(adj, w)
+		}
+	}
+
+	// Similarly, inserting a letter next to the same letter gives
+	// the same word, so only insert after a run of that letter.
+	for j := 0; j <= len(r); j++ {
+		for _, d := range letters {
+			if j < len(r) && r[j] == d {
+				continue
+			}
+			w := string(r[:j]) + string(d) + string(r[j:])
+			if _, ok := words[w]; ok {
+				adj = append(adj, w)
+			}
+		}
+	}
+
+	return adj
+}
+
+// HammingDistance returns the Hamming distance between the words a and b.
+// It panics if a and b are not the same length.
+func HammingDistance(a, b string) int {
+	var d int
+	for a != "" && b != "" {
+		ca, na := utf8.DecodeRuneInString(a)
+		cb, nb := utf8.DecodeRuneInString(b)
+		if ca != cb {
+			d++
+		}
+		a = a[na:]
+		b = b[nb:]
+	}
+	if a != "" || b != "" {
+		panic("word length mismatch")
+	}
+	return d
+}
+
+// isEdit returns whether the words a and b are Levenshtein distance one
+// apart.
+func isEdit(a, b string) bool {
+	ra := []rune(a)
+	rb := []rune(b)
+	switch len(ra) - len(rb) {
+	case 0:
+		return HammingDistance(a, b) == 1
+	case 1:
+		// Make ra the shorter word.
+		ra, rb = rb, ra
+	case -1:
+	default:
+		return false
+	}
+
+	// Find the first difference and check that deleting
+	// the letter in rb at that position gives ra.
+	i := 0
+	for i < len(ra) && ra[i] == rb[i] {
+		i++
+	}
+	return string(ra[i:]) == string(rb[i+1:])
+}
+
+// EditDistance returns the Levenshtein distance between the words a and b.
+func EditDistance(a, b string) int {
+	ra := []rune(a)
+	rb := []rune(b)
+
+	// Keep only the previous and current rows of
+	// the dynamic programming table.
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i, ca := range ra {
+		curr[0] = i + 1
+		for j, cb := range rb {
+			cost := 1
+			if ca == cb {
+				cost = 0
+			}
+			curr[j+1] = min(prev[j]+cost, prev[j+1]+1, curr[j]+1)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// node is a word node in a word graph.
+type node struct {
+	word string
+	id   int64
+
+	// ladder indicates the node is on a
+	// highlighted ladder in a DOT encoding.
+	ladder bool
+}
+
+func (n node) ID() int64      { return n.id }
+func (n node) String() string { return n.word }
+
+// edge is a distance-1 relationship between words in a word graph.
+type edge struct {
+	f, t node
+
+	// ladder indicates the edge is a step of a
+	// highlighted ladder in a DOT encoding.
+	ladder bool
+}
+
+func (e edge) From() graph.Node         { return e.f }
+func (e edge) To() graph.Node           { return e.t }
+func (e edge) ReversedEdge() graph.Edge { return edge{f: e.t, t: e.f, ladder: e.ladder} }
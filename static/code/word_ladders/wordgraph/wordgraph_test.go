@@ -0,0 +1,106 @@
+package wordgraph
+
+import (
+	"math"
+	"testing"
+	"unicode/utf8"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/testgraph"
+)
+
+// testWords is a dictionary holding words of several lengths, with runs
+// of repeated letters to exercise the handling of insertions and deletions
+// that give the same word, and non-ASCII letters.
+var testWords = []string{
+	"cold", "cord", "card", "ward", "warm", "word", "worm", "wore", "core", "care",
+	"cat", "cot", "coat", "cost", "coast", "boast", "boat", "bat", "at", "a",
+	"tool", "too", "to", "toll", "tall", "tell", "ball", "bell", "belt", "bolt",
+	"boot", "bot", "book", "look", "lock", "café", "cafe", "caf", "naïve", "naive",
+}
+
+// conformanceTests are the word graph configurations checked for
+// conformance with the graph API.
+var conformanceTests = []struct {
+	name string
+	kind Kind
+	n    int
+	opts []Option
+}{
+	{name: "Eager", kind: Eager, n: 4},
+	{name: "Lazy", kind: Lazy, n: 4},
+	{name: "LazySlice", kind: LazySlice, n: 4},
+	{name: "EagerLevenshtein", kind: Eager, opts: []Option{Levenshtein()}},
+	{name: "LazyLevenshtein", kind: Lazy, opts: []Option{Levenshtein()}},
+	{name: "LazySliceLevenshtein", kind: LazySlice, opts: []Option{Levenshtein()}},
+}
+
+const (
+	// Word graphs do not return graph.Empty for words
+	// without neighbours.
+	usesEmpty = false
+
+	reversesEdges = true
+)
+
+func TestConformance(t *testing.T) {
+	for _, test := range conformanceTests {
+		b := builder(test.kind, test.n, test.opts...)
+		t.Run(test.name, func(t *testing.T) {
+			t.Run("EdgeExistence", func(t *testing.T) {
+				testgraph.EdgeExistence(t, b, reversesEdges)
+			})
+			t.Run("NodeExistence", func(t *testing.T) {
+				testgraph.NodeExistence(t, b)
+			})
+			t.Run("ReturnAdjacentNodes", func(t *testing.T) {
+				testgraph.ReturnAdjacentNodes(t, b, usesEmpty, reversesEdges)
+			})
+			t.Run("ReturnAllNodes", func(t *testing.T) {
+				testgraph.ReturnAllNodes(t, b, usesEmpty)
+			})
+			t.Run("ReturnNodeSlice", func(t *testing.T) {
+				testgraph.ReturnNodeSlice(t, b, usesEmpty)
+			})
+		})
+	}
+}
+
+// builder returns a testgraph.Builder for word graphs of the given kind.
+// Word graph edges are implied by the words in the graph, so the builder
+// ignores the requested nodes and edges and includes all of testWords,
+// unless no nodes are requested, when the graph is left empty. The edges
+// of the graph are found by comparing every pair of words.
+func builder(kind Kind, n int, opts ...Option) testgraph.Builder {
+	return func(nodes []graph.Node, _ []testgraph.WeightedLine, _, _ float64) (graph.Graph, []graph.Node, []testgraph.Edge, float64, float64, bool) {
+		g := New(kind, n, opts...)
+		if len(nodes) != 0 {
+			for _, w := range testWords {
+				g.Include(w)
+			}
+		}
+		var cfg config
+		for _, opt := range opts {
+			opt(&cfg)
+		}
+		words := graph.NodesOf(g.Nodes())
+		var edges []testgraph.Edge
+		for i, u := range words {
+			for _, v := range words[i+1:] {
+				if joined(u.(node).word, v.(node).word, cfg.edit) {
+					edges = append(edges, edge{f: u.(node), t: v.(node)})
+				}
+			}
+		}
+		return g, words, edges, 0, math.Inf(1), true
+	}
+}
+
+// joined returns whether the words u and v are distance one apart by
+// comparing them directly.
+func joined(u, v string, edit bool) bool {
+	if edit {
+		return EditDistance(u, v) == 1
+	}
+	return utf8.RuneCountInString(u) == utf8.RuneCountInString(v) && HammingDistance(u, v) == 1
+}
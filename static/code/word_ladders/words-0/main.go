@@ -4,52 +4,65 @@
 package main
 
 import (
-	"bufio"
 	"flag"
 	"fmt"
 	"log"
 	"os"
 	"strings"
+	"unicode/utf8"
 
+	"gonum.org/v1/gonum/graph"
 	"gonum.org/v1/gonum/graph/path"
 	"gonum.org/v1/gonum/graph/simple"
+
+	"gonum.org/website/static/code/word_ladders/wordgraph"
 )
 
 func main() {
-	first := flag.String("first", "", "first word in word ladder (required - length must match last)")
-	last := flag.String("last", "", "last word in word ladder (required - length must match first)")
+	first := flag.String("first", "", "first word in word ladder (required - length must match last unless -edit)")
+	last := flag.String("last", "", "last word in word ladder (required - length must match first unless -edit)")
+	edit := flag.Bool("edit", false, "allow ladder steps to insert or delete letters")
+	astar := flag.Bool("astar", false, "use A* search guided by word distance and report the nodes expanded")
+	strict := flag.Bool("strict", false, "reject first and last words that are not in the dictionary instead of warning and including them")
+	var src wordgraph.Source
+	src.RegisterFlags(flag.CommandLine)
 	flag.Parse()
 
-	if *first == "" || *last == "" || len(*first) != len(*last) {
+	if *first == "" || *last == "" || (!*edit && utf8.RuneCountInString(*first) != utf8.RuneCountInString(*last)) {
 		flag.Usage()
 		os.Exit(2)
 	}
 	for _, p := range []*string{first, last} {
 		s := strings.ToLower(*p)
-		if !isWord(s) {
+		if !wordgraph.IsWord(s) {
 			fmt.Fprintf(os.Stderr, "word must not contain punctuation or numerals: %q\n", *p)
 			os.Exit(2)
 		}
 		*p = s
 	}
 
-	// Read in a list of unique words from the input stream.
-	// Include the first and last words in the ladder in case
-	// they do not exists in the dictionary.
+	// Read in a list of unique words from the input stream or
+	// dictionary files, noting whether the first and last words
+	// are in the dictionary. Include the first and last words in
+	// the ladder in case they do not exists in the dictionary.
 	words := map[string]int64{*first: 0, *last: 1}
-	sc := bufio.NewScanner(os.Stdin)
-	for sc.Scan() {
-		w := sc.Text()
-		if len(w) != len(*first) || !isWord(w) {
-			continue
-		}
+	seen := make(map[string]bool)
+	include := func(w string) wordgraph.Status {
 		w = strings.ToLower(w)
-		if _, exists := words[w]; exists {
-			continue
+		if (!*edit && utf8.RuneCountInString(w) != utf8.RuneCountInString(*first)) || !wordgraph.IsWord(w) {
+			return wordgraph.Rejected
+		}
+		if seen[w] {
+			return wordgraph.Duplicate
+		}
+		seen[w] = true
+		if _, exists := words[w]; !exists {
+			words[w] = int64(len(words))
 		}
-		words[w] = int64(len(words))
+		return wordgraph.Accepted
 	}
-	if err := sc.Err(); err != nil {
+	ends := wordgraph.NewEndpoints(*first, *last)
+	if err := src.Read(ends.Wrap(include), os.Stderr); err != nil {
 		log.Fatalf("failed to read word list: %v", err)
 	}
 	list := make([]string, len(words))
@@ -57,65 +70,76 @@ func main() {
 		list[id] = w
 	}
 
-	// Construct a graph using Hamming distance one edges from
-	// list of words.
+	if !ends.Found() {
+		// Reject first and last words that are not in the
+		// dictionary in strict mode, otherwise warn about them
+		// and suggest similar words that are in the dictionary,
+		// found using a word graph of the list.
+		n := utf8.RuneCountInString(*first)
+		var opts []wordgraph.Option
+		if *edit {
+			n = 0
+			opts = append(opts, wordgraph.Levenshtein())
+		}
+		wg := wordgraph.New(wordgraph.Lazy, n, opts...)
+		for _, w := range list {
+			wg.Include(w)
+		}
+		if ends.Report(os.Stderr, wg, *strict) {
+			os.Exit(2)
+		}
+	}
+
+	// Construct a graph using Hamming distance one edges, or
+	// Levenshtein distance one edges if we are allowing letter
+	// insertion and deletion, from list of words, using the
+	// letters that appear in the list.
+	neighbours := wordgraph.Neighbours
+	if *edit {
+		neighbours = wordgraph.EditNeighbours
+	}
+	letters := wordgraph.Letters(words)
 	g := simple.NewUndirectedGraph()
 	for u, uid := range words {
-		for _, v := range neighbours(u, words) {
+		for _, v := range neighbours(u, words, letters) {
 			vid := words[v]
 			g.SetEdge(simple.Edge{F: simple.Node(uid), T: simple.Node(vid)})
 		}
 	}
 
-	// Find the shortest paths from the first word...
-	pth := path.DijkstraFrom(simple.Node(words[*first]), g)
-	// ,,, to the last word.
-	ladder, _ := pth.To(words[strings.ToLower(*last)])
-
-	// Print each step in the ladder.
-	for _, w := range ladder {
-		fmt.Println(list[w.ID()])
+	distance := wordgraph.HammingDistance
+	if *edit {
+		distance = wordgraph.EditDistance
 	}
-}
-
-// isWord returns whether s is entirely alphabetical.
-func isWord(s string) bool {
-	for _, c := range []byte(s) {
-		if lc(c) < 'a' || 'z' < lc(c) {
-			return false
+	var ladder []graph.Node
+	if *astar {
+		// Guide the search towards the last word using the distance
+		// between words as the heuristic and report how much of the
+		// graph was expanded. DijkstraFrom expands all the words that
+		// are reachable from the first word.
+		h := func(x, y graph.Node) float64 {
+			return float64(distance(list[x.ID()], list[y.ID()]))
 		}
+		pth, expanded := path.AStar(simple.Node(words[*first]), simple.Node(words[*last]), g, h)
+		ladder, _ = pth.To(words[*last])
+		fmt.Fprintf(os.Stderr, "A* expanded %d nodes, Dijkstra expands %d nodes\n",
+			expanded, wordgraph.Reachable(g, simple.Node(words[*first])))
+	} else {
+		// Find the shortest paths from the first word...
+		pth := path.DijkstraFrom(simple.Node(words[*first]), g)
+		// ,,, to the last word.
+		ladder, _ = pth.To(words[strings.ToLower(*last)])
+	}
+	if len(ladder) == 0 {
+		// Explain why there is no ladder and suggest
+		// the nearest word that can be reached.
+		word := func(n graph.Node) string { return list[n.ID()] }
+		fmt.Fprintln(os.Stderr, wordgraph.Unreachable(g, simple.Node(words[*first]), simple.Node(words[*last]), word, distance))
+		os.Exit(1)
 	}
-	return true
-}
-
-// lc returns the lower case of b.
-func lc(b byte) byte {
-	return b | 0x20
-}
 
-// neighbours returns a slice of string of words in the words map
-// that are within Hamming distance one from the query word.
-func neighbours(word string, words map[string]int64) []string {
-	var adj []string
-	for j := range word {
-		for d := byte('a'); d <= 'z'; d++ {
-			b := make([]byte, len(word))
-			for i, c := range []byte(word) {
-				if i == j {
-					b[i] = d
-				} else {
-					b[i] = c
-				}
-			}
-			w := string(b)
-			if w != word {
-				if _, ok := words[w]; ok {
-					// We have found a neighbouring word so we
-					// can add it to our list of neighbours.
-					adj = append(adj, w)
-				}
-			}
-		}
+	// Print each step in the ladder.
+	for _, w := range ladder {
+		fmt.Println(list[w.ID()])
 	}
-	return adj
 }
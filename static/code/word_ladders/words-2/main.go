@@ -5,224 +5,90 @@
 package main
 
 import (
-	"bufio"
-	"bytes"
 	"flag"
 	"fmt"
 	"log"
 	"os"
-	"strings"
+	"unicode/utf8"
 
 	"gonum.org/v1/gonum/graph"
 	"gonum.org/v1/gonum/graph/path"
+
+	"gonum.org/website/static/code/word_ladders/wordgraph"
 )
 
 func main() {
-	first := flag.String("first", "", "first word in word ladder (required - length must match last)")
-	last := flag.String("last", "", "last word in word ladder (required - length must match first)")
+	first := flag.String("first", "", "first word in word ladder (required - length must match last unless -edit)")
+	last := flag.String("last", "", "last word in word ladder (required - length must match first unless -edit)")
+	edit := flag.Bool("edit", false, "allow ladder steps to insert or delete letters")
+	alphabet := flag.String("alphabet", "", "letters that may be used in words (default inferred from the word list)")
+	astar := flag.Bool("astar", false, "use A* search guided by word distance and report the nodes expanded")
+	strict := flag.Bool("strict", false, "reject first and last words that are not in the dictionary instead of warning and including them")
+	var weighting wordgraph.Weighting
+	weighting.RegisterFlags(flag.CommandLine)
+	var src wordgraph.Source
+	src.RegisterFlags(flag.CommandLine)
 	flag.Parse()
 
-	if *first == "" || *last == "" || len(*first) != len(*last) {
+	if *first == "" || *last == "" || (!*edit && utf8.RuneCountInString(*first) != utf8.RuneCountInString(*last)) {
 		flag.Usage()
 		os.Exit(2)
 	}
 
-	// Make a new word graph and include the first and last
-	// words in the ladder in case they do not exists in the
-	// dictionary.
-	wg := newWordGraph(len(*first))
-	for _, p := range []*string{first, last} {
-		s := strings.ToLower(*p)
-		if !isWord(s) {
-			fmt.Fprintf(os.Stderr, "word must not contain punctuation or numerals: %q\n", *p)
-			os.Exit(2)
-		}
-		*p = s
-		wg.include(s)
-	}
-
-	// Read in a list of unique words from the input stream.
-	sc := bufio.NewScanner(os.Stdin)
-	for sc.Scan() {
-		wg.include(sc.Text())
-	}
-	if err := sc.Err(); err != nil {
-		log.Fatalf("failed to read word list: %v", err)
-	}
-
-	pth := path.DijkstraFrom(wg.nodeFor(*first), wg)
-	ladder, _ := pth.To(wg.nodeFor(*last).ID())
-
-	for _, w := range ladder {
-		fmt.Println(w)
-	}
-}
-
-// wordGraph is a graph of Hamming distance-1 word paths using lazy implicit
-// edge calculation.
-type wordGraph struct {
-	n     int
-	words []string
-	ids   map[string]int64
-}
-
-// newWordGraph returns a new wordGraph for words of n characters.
-func newWordGraph(n int) wordGraph {
-	return wordGraph{n: n, ids: make(map[string]int64)}
-}
-
-// include adds word to the graph and connects it to its Hamming distance-1
-// neighbours.
-func (g *wordGraph) include(word string) {
-	if len(word) != g.n || !isWord(word) {
-		return
-	}
-	word = strings.ToLower(word)
-	if _, exists := g.ids[word]; exists {
-		return
-	}
-	g.ids[word] = int64(len(g.words))
-	g.words = append(g.words, word)
-}
-
-// isWord returns whether s is entirely alphabetical.
-func isWord(s string) bool {
-	for _, c := range []byte(s) {
-		if lc(c) < 'a' || 'z' < lc(c) {
-			return false
-		}
+	opts := []wordgraph.Option{wordgraph.Alphabet(*alphabet)}
+	n := utf8.RuneCountInString(*first)
+	if *edit {
+		// Ladders that may insert or delete letters
+		// span all word lengths.
+		n = 0
+		opts = append(opts, wordgraph.Levenshtein())
 	}
-	return true
-}
-
-// lc returns the lower case of b.
-func lc(b byte) byte {
-	return b | 0x20
-}
 
-// nodeFor returns a graph.Node representing the word for inclusion in a wordGraph.
-func (g wordGraph) nodeFor(word string) graph.Node {
-	id, ok := g.ids[word]
-	if !ok {
-		return nil
+	// Prefer ladders through words that are common in
+	// the corpus, and weight letter edits by their costs.
+	weights, err := weighting.Options(&src)
+	if err != nil {
+		log.Fatalf("failed to configure ladder weights: %v", err)
 	}
-	return node{word, id}
-}
+	opts = append(opts, weights...)
 
-// From implements the graph.Graph From method.
-func (g wordGraph) From(id int64) graph.Nodes {
-	if uint64(id) >= uint64(len(g.words)) {
-		return graph.Empty
-	}
-	return newNeighbours(g.words[id], g.ids)
-}
-
-// Edge implements the graph.Graph Edge method.
-func (g wordGraph) Edge(uid, vid int64) graph.Edge {
-	if uid == vid {
-		return nil
-	}
-	if uint64(uid) >= uint64(len(g.words)) {
-		return nil
-	}
-	if uint64(vid) >= uint64(len(g.words)) {
-		return nil
-	}
-	u := g.words[uid]
-	v := g.words[vid]
-	d := hamming(u, v)
-	if d != 1 {
-		return nil
+	// Make a new word graph and read in a list of unique words
+	// from the input stream or dictionary files, including the
+	// first and last words in the ladder in case they do not
+	// exist in the dictionary.
+	wg := wordgraph.New(wordgraph.Lazy, n, opts...)
+	*first, *last, err = src.ReadLadder(wg, *first, *last, *strict, os.Stderr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
 	}
-	return edge{f: node{u, uid}, t: node{v, vid}}
-}
 
-// hamming returns the Hamming distance between the words a and b.
-func hamming(a, b string) int {
-	if len(a) != len(b) {
-		panic("word length mismatch")
-	}
-	var d int
-	for i, c := range []byte(a) {
-		if c != b[i] {
-			d++
+	var ladder []graph.Node
+	if *astar {
+		// Guide the search towards the last word using the distance
+		// between words as the heuristic and report how much of the
+		// graph was expanded. DijkstraFrom expands all the words that
+		// are reachable from the first word.
+		pth, expanded := path.AStar(wg.NodeFor(*first), wg.NodeFor(*last), wg, nil)
+		ladder, _ = pth.To(wg.NodeFor(*last).ID())
+		fmt.Fprintf(os.Stderr, "A* expanded %d nodes, Dijkstra expands %d nodes\n",
+			expanded, wordgraph.Reachable(wg, wg.NodeFor(*first)))
+	} else {
+		pth := path.DijkstraFrom(wg.NodeFor(*first), wg)
+		ladder, _ = pth.To(wg.NodeFor(*last).ID())
+	}
+	if len(ladder) == 0 {
+		// Explain why there is no ladder and suggest
+		// the nearest word that can be reached.
+		distance := wordgraph.HammingDistance
+		if *edit {
+			distance = wordgraph.EditDistance
 		}
+		fmt.Fprintln(os.Stderr, wordgraph.Unreachable(wg, wg.NodeFor(*first), wg.NodeFor(*last), nil, distance))
+		os.Exit(1)
 	}
-	return d
-}
-
-// neighbours implements the graph.Nodes interface. It is a deterministic
-// iterator over sets of nodes that represent words with Hamming distance-1
-// from a query word.
-type neighbours struct {
-	word string
-	ids  map[string]int64
-	j    int
-	d    byte
-	buf  []byte
-	curr graph.Node
-}
-
-// completely deterministic - so only gives on solution
-func newNeighbours(word string, ids map[string]int64) *neighbours {
-	return &neighbours{word: word, ids: ids, d: 'a', buf: make([]byte, len(word))}
-}
-
-// Len implements the graph.Nodes Len method. It returns -1 to indicate the iterator
-// has an unknown number of of items.
-func (it *neighbours) Len() int { return -1 }
-
-// Next implements the graph.Nodes Next method.
-func (it *neighbours) Next() bool {
-	// The Next method is implemented using the same algorithm as used by the
-	// neighbours function in the original naive implementation of the search.
 
-	for it.j < len(it.word) {
-		for i, c := range []byte(it.word) {
-			if i == it.j {
-				it.buf[i] = it.d
-			} else {
-				it.buf[i] = c
-			}
-		}
-		it.d++
-		if it.d > 'z' {
-			it.j++
-			it.d = 'a'
-		}
-
-		if !bytes.Equal(it.buf, []byte(it.word)) {
-			// We have found a neighbouring word so we can return
-			// true and set the current word to this neighbour.
-			if _, ok := it.ids[string(it.buf)]; ok {
-				w := string(it.buf)
-				it.curr = node{w, it.ids[w]}
-				return true
-			}
-		}
+	for _, w := range ladder {
+		fmt.Println(w)
 	}
-	it.curr = nil
-	return false
 }
-
-// Node implements the graph.Nodes Node method.
-func (it *neighbours) Node() graph.Node { return it.curr }
-
-// Reset implements the graph.Nodes Reset method.
-func (it *neighbours) Reset() { it.j, it.d = 0, 'a' }
-
-// node is a word node in a wordGraph.
-type node struct {
-	word string
-	id   int64
-}
-
-func (n node) ID() int64      { return n.id }
-func (n node) String() string { return n.word }
-
-// edge is a Hamming distance-1 relationship between words in a wordGraph.
-type edge struct{ f, t node }
-
-func (e edge) From() graph.Node         { return e.f }
-func (e edge) To() graph.Node           { return e.t }
-func (e edge) ReversedEdge() graph.Edge { return edge{f: e.t, t: e.f} }
@@ -0,0 +1,81 @@
+// words-2b is a simple graph-based program to find word ladders
+// between pairs of words in a dictionary. It stores words as nodes
+// within the graph, edges are implied by Hamming distance and are
+// enumerated lazily when neighbouring nodes are queried. Ladders
+// are found by breadth-first searches from both ends of the ladder
+// that meet in the middle.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"unicode/utf8"
+
+	"gonum.org/v1/gonum/graph"
+
+	"gonum.org/website/static/code/word_ladders/wordgraph"
+)
+
+func main() {
+	first := flag.String("first", "", "first word in word ladder (required - length must match last unless -edit)")
+	last := flag.String("last", "", "last word in word ladder (required - length must match first unless -edit)")
+	all := flag.Bool("all", false, "find all shortest word ladders")
+	edit := flag.Bool("edit", false, "allow ladder steps to insert or delete letters")
+	alphabet := flag.String("alphabet", "", "letters that may be used in words (default inferred from the word list)")
+	strict := flag.Bool("strict", false, "reject first and last words that are not in the dictionary instead of warning and including them")
+	var src wordgraph.Source
+	src.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	if *first == "" || *last == "" || (!*edit && utf8.RuneCountInString(*first) != utf8.RuneCountInString(*last)) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	opts := []wordgraph.Option{wordgraph.Alphabet(*alphabet)}
+	n := utf8.RuneCountInString(*first)
+	if *edit {
+		// Ladders that may insert or delete letters
+		// span all word lengths.
+		n = 0
+		opts = append(opts, wordgraph.Levenshtein())
+	}
+
+	// Make a new word graph and read in a list of unique words
+	// from the input stream or dictionary files, including the
+	// first and last words in the ladder in case they do not
+	// exist in the dictionary.
+	wg := wordgraph.New(wordgraph.Lazy, n, opts...)
+	var err error
+	*first, *last, err = src.ReadLadder(wg, *first, *last, *strict, os.Stderr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	var (
+		ladders  [][]graph.Node
+		expanded int
+	)
+	if *all {
+		ladders, expanded = wordgraph.BidirectionalAllBetween(wg, wg.NodeFor(*first), wg.NodeFor(*last))
+	} else {
+		var ladder []graph.Node
+		ladder, expanded = wordgraph.BidirectionalBetween(wg, wg.NodeFor(*first), wg.NodeFor(*last))
+		if ladder != nil {
+			ladders = [][]graph.Node{ladder}
+		}
+	}
+	fmt.Fprintf(os.Stderr, "bidirectional search expanded %d nodes\n", expanded)
+
+	for _, l := range ladders {
+		if *all {
+			fmt.Println(l)
+			continue
+		}
+		for _, w := range l {
+			fmt.Println(w)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// words-serve is a long-running server that answers word ladder queries
+// over a dictionary with JSON responses. The dictionary is read once on
+// start, from standard input or the -dict files, into a graph for each
+// length of word, with edges implied by Hamming distance and enumerated
+// lazily when neighbouring nodes are queried.
+//
+// The server answers GET requests to these endpoints:
+//
+//	/ladder?first=cold&last=warm      a shortest ladder
+//	/ladders?first=cold&last=warm     all shortest ladders
+//	/neighbours?word=cold             the neighbours of a word
+//	/component?word=cold[&with=warm]  the connected component of a word
+//
+// Components are named by their alphabetically first word. Failed queries
+// are answered with an error status and a JSON object holding an error
+// message.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"gonum.org/website/static/code/word_ladders/wordgraph"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "address to listen on")
+	alphabet := flag.String("alphabet", "", "letters that may be used in words (default inferred from the word list)")
+	timeout := flag.Duration("timeout", 10*time.Second, "time allowed to answer a request (must be greater than 0)")
+	var src wordgraph.Source
+	src.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	if *timeout <= 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Read in a list of unique words from the input stream
+	// or dictionary files.
+	dict, err := src.ReadAll(os.Stderr)
+	if err != nil {
+		log.Fatalf("failed to read word list: %v", err)
+	}
+	s, err := newServer(bytes.NewReader(dict), *alphabet)
+	if err != nil {
+		log.Fatalf("failed to read word list: %v", err)
+	}
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           s.handler(*timeout),
+		ReadHeaderTimeout: *timeout,
+		ReadTimeout:       *timeout,
+		// Allow the request handler to time out and
+		// write its response before the connection is
+		// closed.
+		WriteTimeout: 2 * *timeout,
+	}
+	log.Printf("serving ladders on %s", *addr)
+	log.Fatal(srv.ListenAndServe())
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/path"
+	"gonum.org/v1/gonum/graph/topo"
+
+	"gonum.org/website/static/code/word_ladders/wordgraph"
+)
+
+// server answers ladder queries over a dictionary. It holds a word graph
+// for each length of word in the dictionary, and the connected components
+// of the graphs.
+type server struct {
+	graphs map[int]wordgraph.Graph
+
+	// components maps each word to the alphabetically
+	// first word in its connected component.
+	components map[string]string
+
+	mux *http.ServeMux
+}
+
+// newServer returns a server for the dictionary read from r. Words are
+// restricted to letters in the given alphabet, or if alphabet is empty,
+// letters inferred from the dictionary.
+func newServer(r io.Reader, alphabet string) (*server, error) {
+	s := &server{
+		graphs:     make(map[int]wordgraph.Graph),
+		components: make(map[string]string),
+		mux:        http.NewServeMux(),
+	}
+
+	// Read in a list of unique words from the input stream
+	// into a word graph for each length of word.
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		word := strings.ToLower(sc.Text())
+		if !wordgraph.IsWord(word) {
+			continue
+		}
+		n := utf8.RuneCountInString(word)
+		g, ok := s.graphs[n]
+		if !ok {
+			g = wordgraph.New(wordgraph.Lazy, n, wordgraph.Alphabet(alphabet))
+			s.graphs[n] = g
+		}
+		g.Include(word)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, g := range s.graphs {
+		for _, c := range topo.ConnectedComponents(g) {
+			first := fmt.Sprint(c[0])
+			for _, u := range c[1:] {
+				if w := fmt.Sprint(u); w < first {
+					first = w
+				}
+			}
+			for _, u := range c {
+				s.components[fmt.Sprint(u)] = first
+			}
+		}
+	}
+
+	s.mux.HandleFunc("/ladder", s.ladder)
+	s.mux.HandleFunc("/ladders", s.ladders)
+	s.mux.HandleFunc("/neighbours", s.neighbours)
+	s.mux.HandleFunc("/component", s.component)
+	return s, nil
+}
+
+// handler returns the HTTP handler for the server. Requests that are not
+// answered within timeout fail with a 503 Service Unavailable status and
+// a JSON error response.
+func (s *server) handler(timeout time.Duration) http.Handler {
+	h := http.TimeoutHandler(s.mux, timeout, `{"error":"request timed out"}`+"\n")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The timeout handler does not set a content type
+		// for its error response, and replaces this one with
+		// the content type set by the mux when it answers
+		// in time.
+		w.Header().Set("Content-Type", "application/json")
+		h.ServeHTTP(w, r)
+	})
+}
+
+// ladderResponse is the response to ladder and ladders queries.
+type ladderResponse struct {
+	First   string     `json:"first"`
+	Last    string     `json:"last"`
+	Steps   int        `json:"steps"`
+	Ladder  []string   `json:"ladder,omitempty"`
+	Ladders [][]string `json:"ladders,omitempty"`
+}
+
+// ladder responds with a shortest ladder between the words given
+// by the first and last query parameters.
+func (s *server) ladder(w http.ResponseWriter, r *http.Request) {
+	g, first, last, ok := s.doublet(w, r)
+	if !ok {
+		return
+	}
+	ladder, _ := wordgraph.BidirectionalBetween(cancellable{Graph: g, ctx: r.Context()}, first, last)
+	if r.Context().Err() != nil {
+		// The request has timed out and been answered.
+		return
+	}
+	if len(ladder) == 0 {
+		writeError(w, http.StatusNotFound, "no ladder from %q to %q", first, last)
+		return
+	}
+	writeJSON(w, ladderResponse{
+		First:  fmt.Sprint(first),
+		Last:   fmt.Sprint(last),
+		Steps:  len(ladder) - 1,
+		Ladder: words(ladder),
+	})
+}
+
+// ladders responds with all the shortest ladders between the words
+// given by the first and last query parameters, in sorted order.
+func (s *server) ladders(w http.ResponseWriter, r *http.Request) {
+	g, first, last, ok := s.doublet(w, r)
+	if !ok {
+		return
+	}
+	pth := path.DijkstraAllFrom(first, cancellable{Graph: g, ctx: r.Context()})
+	if r.Context().Err() != nil {
+		// The request has timed out and been answered.
+		// The search may have been cut short, so do not
+		// enumerate the ladders it found.
+		return
+	}
+	ladders, _ := pth.AllTo(last.ID())
+	if len(ladders) == 0 {
+		writeError(w, http.StatusNotFound, "no ladder from %q to %q", first, last)
+		return
+	}
+	resp := ladderResponse{
+		First:   fmt.Sprint(first),
+		Last:    fmt.Sprint(last),
+		Steps:   len(ladders[0]) - 1,
+		Ladders: make([][]string, len(ladders)),
+	}
+	for i, l := range ladders {
+		resp.Ladders[i] = words(l)
+	}
+	sort.Slice(resp.Ladders, func(i, j int) bool {
+		return strings.Join(resp.Ladders[i], " ") < strings.Join(resp.Ladders[j], " ")
+	})
+	writeJSON(w, resp)
+}
+
+// neighboursResponse is the response to neighbours queries.
+type neighboursResponse struct {
+	Word       string   `json:"word"`
+	Neighbours []string `json:"neighbours"`
+}
+
+// neighbours responds with the sorted neighbours of the word given
+// by the word query parameter.
+func (s *server) neighbours(w http.ResponseWriter, r *http.Request) {
+	g, u, ok := s.lookup(w, r, "word")
+	if !ok {
+		return
+	}
+	adj := words(graph.NodesOf(g.From(u.ID())))
+	sort.Strings(adj)
+	writeJSON(w, neighboursResponse{Word: fmt.Sprint(u), Neighbours: adj})
+}
+
+// componentResponse is the response to component queries.
+type componentResponse struct {
+	Word string `json:"word"`
+
+	// Component is the alphabetically first
+	// word in the component holding Word.
+	Component string `json:"component"`
+
+	// Connected is whether Word is in the
+	// same component as the word given by
+	// the with query parameter.
+	Connected *bool `json:"connected,omitempty"`
+}
+
+// component responds with the connected component holding the word
+// given by the word query parameter, and if the with query parameter
+// is given, whether that word is in the same component.
+func (s *server) component(w http.ResponseWriter, r *http.Request) {
+	_, u, ok := s.lookup(w, r, "word")
+	if !ok {
+		return
+	}
+	resp := componentResponse{Word: fmt.Sprint(u), Component: s.components[fmt.Sprint(u)]}
+	if r.URL.Query().Has("with") {
+		_, v, ok := s.lookup(w, r, "with")
+		if !ok {
+			return
+		}
+		connected := s.components[fmt.Sprint(v)] == resp.Component
+		resp.Connected = &connected
+	}
+	writeJSON(w, resp)
+}
+
+// doublet returns the graph holding the words given by the first and
+// last query parameters, and their nodes. If the words are not a valid
+// doublet, doublet writes an error response and returns false.
+func (s *server) doublet(w http.ResponseWriter, r *http.Request) (g wordgraph.Graph, first, last graph.Node, ok bool) {
+	g, first, ok = s.lookup(w, r, "first")
+	if !ok {
+		return nil, nil, nil, false
+	}
+	_, last, ok = s.lookup(w, r, "last")
+	if !ok {
+		return nil, nil, nil, false
+	}
+	if utf8.RuneCountInString(fmt.Sprint(first)) != utf8.RuneCountInString(fmt.Sprint(last)) {
+		writeError(w, http.StatusBadRequest, "length of first must match last: %q %q", first, last)
+		return nil, nil, nil, false
+	}
+	return g, first, last, true
+}
+
+// lookup returns the graph holding the word given by the named query
+// parameter, and its node. If the parameter is missing or the word is
+// not in the dictionary, lookup writes an error response and returns
+// false.
+func (s *server) lookup(w http.ResponseWriter, r *http.Request, param string) (g wordgraph.Graph, u graph.Node, ok bool) {
+	word := strings.ToLower(r.URL.Query().Get(param))
+	if word == "" {
+		writeError(w, http.StatusBadRequest, "missing %s parameter", param)
+		return nil, nil, false
+	}
+	g, ok = s.graphs[utf8.RuneCountInString(word)]
+	if ok {
+		u = g.NodeFor(word)
+	}
+	if u == nil {
+		writeError(w, http.StatusNotFound, "word not in dictionary: %q", word)
+		return nil, nil, false
+	}
+	return g, u, true
+}
+
+// cancellable is a word graph whose words have no neighbours once its
+// context is done, so that searches over it stop when a request times
+// out or is cancelled.
+type cancellable struct {
+	wordgraph.Graph
+	ctx context.Context
+}
+
+// From implements the graph.Graph From method.
+func (g cancellable) From(id int64) graph.Nodes {
+	if g.ctx.Err() != nil {
+		return graph.Empty
+	}
+	return g.Graph.From(id)
+}
+
+// words returns the words of the nodes.
+func words(nodes []graph.Node) []string {
+	w := make([]string, len(nodes))
+	for i, n := range nodes {
+		w[i] = fmt.Sprint(n)
+	}
+	return w
+}
+
+// errorResponse is the response to a failed query.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// writeError writes an error response with the given status code.
+func writeError(w http.ResponseWriter, code int, format string, args ...interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	writeBody(w, errorResponse{Error: fmt.Sprintf(format, args...)})
+}
+
+// writeJSON writes a successful response holding v.
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	writeBody(w, v)
+}
+
+func writeBody(w http.ResponseWriter, v interface{}) {
+	err := json.NewEncoder(w).Encode(v)
+	if err != nil {
+		log.Printf("failed to write response: %v", err)
+	}
+}
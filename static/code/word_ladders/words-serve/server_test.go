@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/path"
+
+	"gonum.org/website/static/code/word_ladders/wordgraph"
+)
+
+// dictionary is the word list served in tests. It holds a disconnected
+// four letter word, words of other lengths, a duplicate differing by case,
+// and a word rejected for holding punctuation.
+const dictionary = `cold
+cord
+card
+ward
+warm
+word
+worm
+wore
+core
+care
+quiz
+cat
+cot
+dog
+Cold
+it's
+`
+
+var serverTests = []struct {
+	query string
+	code  int
+	want  string
+}{
+	{
+		query: "/ladder?first=cold&last=warm",
+		code:  http.StatusOK,
+		want:  `{"first":"cold","last":"warm","steps":4,"ladder":["cold","cord","word","worm","warm"]}`,
+	},
+	{
+		query: "/ladder?first=COLD&last=Cold",
+		code:  http.StatusOK,
+		want:  `{"first":"cold","last":"cold","steps":0,"ladder":["cold"]}`,
+	},
+	{
+		query: "/ladders?first=cold&last=warm",
+		code:  http.StatusOK,
+		want: `{"first":"cold","last":"warm","steps":4,"ladders":[
+			["cold","cord","card","ward","warm"],
+			["cold","cord","word","ward","warm"],
+			["cold","cord","word","worm","warm"]
+		]}`,
+	},
+	{
+		query: "/neighbours?word=word",
+		code:  http.StatusOK,
+		want:  `{"word":"word","neighbours":["cord","ward","wore","worm"]}`,
+	},
+	{
+		query: "/neighbours?word=quiz",
+		code:  http.StatusOK,
+		want:  `{"word":"quiz","neighbours":[]}`,
+	},
+	{
+		query: "/component?word=warm",
+		code:  http.StatusOK,
+		want:  `{"word":"warm","component":"card"}`,
+	},
+	{
+		query: "/component?word=warm&with=cold",
+		code:  http.StatusOK,
+		want:  `{"word":"warm","component":"card","connected":true}`,
+	},
+	{
+		query: "/component?word=warm&with=quiz",
+		code:  http.StatusOK,
+		want:  `{"word":"warm","component":"card","connected":false}`,
+	},
+	{
+		query: "/component?word=cat&with=dog",
+		code:  http.StatusOK,
+		want:  `{"word":"cat","component":"cat","connected":false}`,
+	},
+	{
+		query: "/ladder?first=cold&last=quiz",
+		code:  http.StatusNotFound,
+		want:  `{"error":"no ladder from \"cold\" to \"quiz\""}`,
+	},
+	{
+		query: "/ladders?first=cold&last=quiz",
+		code:  http.StatusNotFound,
+		want:  `{"error":"no ladder from \"cold\" to \"quiz\""}`,
+	},
+	{
+		query: "/ladder?first=cold&last=cat",
+		code:  http.StatusBadRequest,
+		want:  `{"error":"length of first must match last: \"cold\" \"cat\""}`,
+	},
+	{
+		query: "/ladder?first=cold",
+		code:  http.StatusBadRequest,
+		want:  `{"error":"missing last parameter"}`,
+	},
+	{
+		query: "/neighbours?word=cola",
+		code:  http.StatusNotFound,
+		want:  `{"error":"word not in dictionary: \"cola\""}`,
+	},
+	{
+		query: "/component?word=it's",
+		code:  http.StatusNotFound,
+		want:  `{"error":"word not in dictionary: \"it's\""}`,
+	},
+	{
+		query: "/component?word=cold&with=",
+		code:  http.StatusBadRequest,
+		want:  `{"error":"missing with parameter"}`,
+	},
+}
+
+func TestServer(t *testing.T) {
+	s, err := newServer(strings.NewReader(dictionary), "")
+	if err != nil {
+		t.Fatalf("failed to make server: %v", err)
+	}
+	srv := httptest.NewServer(s.handler(time.Minute))
+	defer srv.Close()
+
+	for _, test := range serverTests {
+		code, typ, got, err := get(srv.URL + test.query)
+		if err != nil {
+			t.Errorf("failed to query %s: %v", test.query, err)
+			continue
+		}
+		if code != test.code {
+			t.Errorf("unexpected status for %s: got:%d want:%d", test.query, code, test.code)
+		}
+		if typ != "application/json" {
+			t.Errorf("unexpected content type for %s: got:%q want:%q", test.query, typ, "application/json")
+		}
+		var want interface{}
+		err = json.Unmarshal([]byte(test.want), &want)
+		if err != nil {
+			t.Fatalf("invalid test response for %s: %v", test.query, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("unexpected response for %s:\ngot: %v\nwant:%v", test.query, got, want)
+		}
+	}
+}
+
+func TestServerTimeout(t *testing.T) {
+	s, err := newServer(strings.NewReader(dictionary), "")
+	if err != nil {
+		t.Fatalf("failed to make server: %v", err)
+	}
+
+	// Hold the ladder search until the request has
+	// timed out, so the result does not depend on
+	// how long the search takes.
+	release := make(chan struct{})
+	s.graphs[4] = blocking{Graph: s.graphs[4], release: release}
+	done := make(chan struct{})
+	s.mux.HandleFunc("/wait", func(w http.ResponseWriter, r *http.Request) {
+		defer close(done)
+		s.ladders(w, r)
+		if r.Context().Err() == nil {
+			t.Error("ladders returned before the request was cancelled")
+		}
+	})
+	srv := httptest.NewServer(s.handler(time.Millisecond))
+	defer srv.Close()
+
+	code, typ, got, err := get(srv.URL + "/wait?first=cold&last=warm")
+	close(release)
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("unexpected status: got:%d want:%d", code, http.StatusServiceUnavailable)
+	}
+	if typ != "application/json" {
+		t.Errorf("unexpected content type: got:%q want:%q", typ, "application/json")
+	}
+	want := map[string]interface{}{"error": "request timed out"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected response: got:%v want:%v", got, want)
+	}
+	<-done
+}
+
+// blocking is a word graph whose From method blocks until release is
+// closed.
+type blocking struct {
+	wordgraph.Graph
+	release <-chan struct{}
+}
+
+func (g blocking) From(id int64) graph.Nodes {
+	<-g.release
+	return g.Graph.From(id)
+}
+
+func TestCancellable(t *testing.T) {
+	s, err := newServer(strings.NewReader(dictionary), "")
+	if err != nil {
+		t.Fatalf("failed to make server: %v", err)
+	}
+	g := s.graphs[4]
+	cold, warm := g.NodeFor("cold"), g.NodeFor("warm")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cg := cancellable{Graph: g, ctx: ctx}
+	if n := cg.From(cold.ID()).Len(); n == 0 {
+		t.Error("unexpected empty neighbours before cancellation")
+	}
+	if got, _ := path.DijkstraAllFrom(cold, cg).AllTo(warm.ID()); len(got) == 0 {
+		t.Error("expected ladders before cancellation")
+	}
+
+	cancel()
+	if n := cg.From(cold.ID()).Len(); n != 0 {
+		t.Errorf("unexpected neighbours after cancellation: got:%d want:0", n)
+	}
+	if got, _ := path.DijkstraAllFrom(cold, cg).AllTo(warm.ID()); len(got) != 0 {
+		t.Errorf("unexpected ladders after cancellation: %v", got)
+	}
+	if got, _ := wordgraph.BidirectionalBetween(cg, cold, warm); got != nil {
+		t.Errorf("unexpected ladder after cancellation: %v", got)
+	}
+}
+
+// get returns the status code, content type and decoded JSON body of
+// a GET request to url.
+func get(url string) (code int, typ string, body interface{}, err error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return 0, "", nil, err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, "", nil, err
+	}
+	err = json.Unmarshal(b, &body)
+	return resp.StatusCode, resp.Header.Get("Content-Type"), body, err
+}